@@ -0,0 +1,280 @@
+package codon
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+// The largest number of codons changed to remove one group of overlapping
+// sites.
+const maxEditsPerSite = 3
+
+// A change of one codon of a coding sequence.
+type CodonEdit struct {
+	// The position of the first base of the codon in the sequence.
+	Position int
+
+	// The amino acids encoded by the original and replacement codons. They
+	// are the same for a synonymous change.
+	AminoAcid            byte
+	ReplacementAminoAcid byte
+
+	Original    string
+	Replacement string
+}
+
+// Return true if the edit does not change the amino acid.
+func (edit CodonEdit) IsSynonymous() bool {
+	return edit.AminoAcid == edit.ReplacementAminoAcid
+}
+
+// Return a description of the edit, e.g. "GGT123GGC (G)" or, if it changes
+// the amino acid, "GGT123GCT (G>A)".
+func (edit CodonEdit) String() string {
+	if edit.IsSynonymous() {
+		return fmt.Sprintf("%s%d%s (%c)", edit.Original, edit.Position, edit.Replacement, edit.AminoAcid)
+	}
+	return fmt.Sprintf("%s%d%s (%c>%c)",
+		edit.Original, edit.Position, edit.Replacement, edit.AminoAcid, edit.ReplacementAminoAcid)
+}
+
+// The result of removing the recognition sites of a batch of enzymes from a
+// coding sequence.
+type Domestication struct {
+	// The coding sequence with every edit applied.
+	Sequence string
+	Edits    []CodonEdit
+
+	// The sites found in the original sequence that the edits remove, and
+	// those that cannot be removed by synonymous changes, e.g. sites that
+	// only overlap methionine and tryptophan codons.
+	RemovedSites    []enzyme.RecognitionSiteResult
+	UnresolvedSites []enzyme.RecognitionSiteResult
+
+	// The sites of the batch found when searching the edited sequence.
+	Verification []enzyme.RecognitionSiteResult
+}
+
+// Return true if the edited sequence has no sites of the batch.
+func (domestication *Domestication) IsDomesticated() bool {
+	return len(domestication.Verification) == 0
+}
+
+// A group of sites whose codons overlap. The sites are removed together.
+type siteCluster struct {
+	sites      []enzyme.RecognitionSiteResult
+	firstCodon int
+	lastCodon  int
+}
+
+/*
+Remove every recognition site of the batch from a coding sequence read in
+the frame, 0, 1 or 2, using synonymous codon changes.
+
+Sites whose codons overlap are removed together with the fewest codon
+changes that remove them all without creating a site of the batch. When
+several changes are equally small the codons the host uses most, by their
+relative adaptiveness in the usage table, are chosen. Sites that cannot be
+removed with up to three changes, or that lie outside of the codons, are
+reported as unresolved.
+*/
+func Domesticate(sequence string, frame int, batch *enzyme.RestrictionBatch, table *UsageTable) (Domestication, error) {
+	sequence = strings.ToUpper(sequence)
+	if _, err := Translate(sequence, frame); err != nil {
+		return Domestication{}, err
+	}
+
+	domestication := Domestication{
+		Sequence:        sequence,
+		Edits:           []CodonEdit{},
+		RemovedSites:    []enzyme.RecognitionSiteResult{},
+		UnresolvedSites: []enzyme.RecognitionSiteResult{},
+	}
+
+	maxSiteLength := 0
+	for _, ez := range batch.Enzymes {
+		maxSiteLength = max(maxSiteLength, ez.Length)
+	}
+
+	for _, cluster := range clusterSites(batch.FindAll(sequence, false), frame, len(sequence)) {
+		if cluster.firstCodon > cluster.lastCodon {
+			domestication.UnresolvedSites = append(domestication.UnresolvedSites, cluster.sites...)
+			continue
+		}
+
+		edits, ok := removeCluster(domestication.Sequence, frame, cluster, batch, table, maxSiteLength)
+		if !ok {
+			domestication.UnresolvedSites = append(domestication.UnresolvedSites, cluster.sites...)
+			continue
+		}
+
+		domestication.Sequence = applyEdits(domestication.Sequence, edits)
+		domestication.Edits = append(domestication.Edits, edits...)
+		domestication.RemovedSites = append(domestication.RemovedSites, cluster.sites...)
+	}
+
+	domestication.Verification = batch.FindAll(domestication.Sequence, false)
+	return domestication, nil
+}
+
+// Group the sites, sorted by position, into clusters of sites that share a
+// codon. A cluster of sites that lie outside of the codons has a last codon
+// before its first.
+func clusterSites(sites []enzyme.RecognitionSiteResult, frame int, length int) []siteCluster {
+	codonCount := (length - frame) / 3
+	clusters := []siteCluster{}
+	for _, site := range sites {
+		end := site.RecognitionSiteIndex + site.Enzyme.Length
+		firstCodon := max((site.RecognitionSiteIndex-frame)/3, 0)
+		lastCodon := min((end-1-frame)/3, codonCount-1)
+		if end-1 < frame {
+			lastCodon = -1
+		}
+
+		if len(clusters) > 0 {
+			last := &clusters[len(clusters)-1]
+			if last.firstCodon <= last.lastCodon && firstCodon <= lastCodon && firstCodon <= last.lastCodon {
+				last.sites = append(last.sites, site)
+				last.lastCodon = max(last.lastCodon, lastCodon)
+				continue
+			}
+		}
+		clusters = append(clusters, siteCluster{[]enzyme.RecognitionSiteResult{site}, firstCodon, lastCodon})
+	}
+	return clusters
+}
+
+// Return the fewest, and then best adapted, codon edits that remove every
+// site of the cluster without creating a new site.
+func removeCluster(
+	sequence string,
+	frame int,
+	cluster siteCluster,
+	batch *enzyme.RestrictionBatch,
+	table *UsageTable,
+	maxSiteLength int,
+) ([]CodonEdit, bool) {
+	codons := []int{}
+	for codon := cluster.firstCodon; codon <= cluster.lastCodon; codon++ {
+		codons = append(codons, codon)
+	}
+
+	for editCount := 1; editCount <= min(maxEditsPerSite, len(codons)); editCount++ {
+		var best []CodonEdit
+		bestScore := math.Inf(-1)
+
+		forEachCombination(len(codons), editCount, func(chosen []int) {
+			edits := make([]CodonEdit, len(chosen))
+			for i, index := range chosen {
+				position := frame + codons[index]*3
+				original := sequence[position : position+3]
+				edits[i] = CodonEdit{Position: position, AminoAcid: GeneticCode[original], Original: original}
+			}
+
+			forEachReplacement(edits, table, 0, func() {
+				score := editScore(edits, table)
+				if score <= bestScore {
+					return
+				}
+				if removesCluster(sequence, edits, cluster, batch, maxSiteLength) {
+					best = append([]CodonEdit{}, edits...)
+					bestScore = score
+				}
+			})
+		})
+
+		if best != nil {
+			return best, true
+		}
+	}
+	return nil, false
+}
+
+// Call visit with every combination of k of the indexes 0 to n-1 in
+// ascending order.
+func forEachCombination(n int, k int, visit func(chosen []int)) {
+	chosen := make([]int, 0, k)
+	var choose func(start int)
+	choose = func(start int) {
+		if len(chosen) == k {
+			visit(chosen)
+			return
+		}
+		for i := start; i < n; i++ {
+			chosen = append(chosen, i)
+			choose(i + 1)
+			chosen = chosen[:len(chosen)-1]
+		}
+	}
+	choose(0)
+}
+
+// Call visit with every assignment of synonymous replacements to the edits.
+func forEachReplacement(edits []CodonEdit, table *UsageTable, index int, visit func()) {
+	if index == len(edits) {
+		visit()
+		return
+	}
+	for _, synonym := range table.Synonyms(edits[index].Original) {
+		edits[index].Replacement = synonym
+		edits[index].ReplacementAminoAcid = edits[index].AminoAcid
+		forEachReplacement(edits, table, index+1, visit)
+	}
+}
+
+// Return the log of the product of the relative adaptiveness of the
+// replacement codons.
+func editScore(edits []CodonEdit, table *UsageTable) float64 {
+	score := 0.0
+	for _, edit := range edits {
+		score += math.Log(max(table.RelativeAdaptiveness(edit.Replacement), 1e-6))
+	}
+	return score
+}
+
+// Return true if, after the edits, none of the cluster's sites remain and
+// no site of the batch overlaps an edited codon.
+func removesCluster(
+	sequence string,
+	edits []CodonEdit,
+	cluster siteCluster,
+	batch *enzyme.RestrictionBatch,
+	maxSiteLength int,
+) bool {
+	first, last := edits[0].Position, edits[len(edits)-1].Position+3
+	for _, site := range cluster.sites {
+		first = min(first, site.RecognitionSiteIndex)
+		last = max(last, site.RecognitionSiteIndex+site.Enzyme.Length)
+	}
+
+	windowStart := max(first-maxSiteLength+1, 0)
+	windowEnd := min(last+maxSiteLength-1, len(sequence))
+	window := applyEdits(sequence, edits)[windowStart:windowEnd]
+
+	for _, result := range batch.FindAll(window, false) {
+		start := windowStart + result.RecognitionSiteIndex
+		end := start + result.Enzyme.Length
+		for _, edit := range edits {
+			if start < edit.Position+3 && edit.Position < end {
+				return false
+			}
+		}
+		for _, site := range cluster.sites {
+			if site.Enzyme.Name == result.Enzyme.Name && site.RecognitionSiteIndex == start && site.Strand == result.Strand {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func applyEdits(sequence string, edits []CodonEdit) string {
+	edited := []byte(sequence)
+	for _, edit := range edits {
+		copy(edited[edit.Position:], edit.Replacement)
+	}
+	return string(edited)
+}
@@ -0,0 +1,98 @@
+package codon
+
+import (
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/db"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+func TestDomesticate(t *testing.T) {
+	// A BsaI site across the glycine and leucine codons and a BbsI (BpiI)
+	// site read from the crick strand, GTCTTC, across serine and serine.
+	sequence := "ATG" + "GGT" + "CTC" + "AAA" + "GTC" + "TTC" + "TAA"
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["BsaI"], db.Enzymes["BbsI"])
+
+	domestication, err := Domesticate(sequence, 0, &batch, EscherichiaColi)
+	if err != nil {
+		t.Fatalf("Failed to domesticate: %s", err)
+	}
+
+	if !domestication.IsDomesticated() || len(domestication.UnresolvedSites) != 0 {
+		t.Errorf("Expected every site to be removed, found %v", domestication.Verification)
+	}
+	if len(domestication.Edits) != 2 || len(domestication.RemovedSites) != 2 {
+		t.Fatalf("Expected one edit for each site, got %v", domestication.Edits)
+	}
+
+	// GGC and CTG are both the preferred codons, the first codon is changed.
+	if edit := domestication.Edits[0]; edit.Position != 3 || edit.Original != "GGT" || edit.Replacement != "GGC" {
+		t.Errorf("Expected GGT3GGC, got %s", edit)
+	}
+
+	original, _ := Translate(sequence, 0)
+	edited, _ := Translate(domestication.Sequence, 0)
+	if original != edited {
+		t.Errorf("Expected the protein %s to be unchanged, got %s", original, edited)
+	}
+}
+
+func TestDomesticateDoesNotCreateSites(t *testing.T) {
+	// Changing GGT to GGC would create a HaeIII site, GGCC.
+	sequence := "ATG" + "GGT" + "CTC" + "AAA" + "TAA"
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["BsaI"], db.Enzymes["HaeIII"])
+
+	domestication, err := Domesticate(sequence, 0, &batch, EscherichiaColi)
+	if err != nil {
+		t.Fatalf("Failed to domesticate: %s", err)
+	}
+	if len(domestication.Edits) != 1 {
+		t.Fatalf("Expected 1 edit, got %v", domestication.Edits)
+	}
+	if edit := domestication.Edits[0]; edit.Position != 6 || edit.Replacement != "CTG" {
+		t.Errorf("Expected CTC6CTG, got %s", edit)
+	}
+	if !domestication.IsDomesticated() {
+		t.Errorf("Expected no sites in %s, found %v", domestication.Sequence, domestication.Verification)
+	}
+}
+
+func TestDomesticateFrame(t *testing.T) {
+	// In frame 1 the site spans the codons GGT and CTC.
+	sequence := "C" + "ATG" + "GGT" + "CTC" + "AAA"
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["BsaI"])
+
+	domestication, err := Domesticate(sequence, 1, &batch, SaccharomycesCerevisiae)
+	if err != nil {
+		t.Fatalf("Failed to domesticate: %s", err)
+	}
+
+	// Yeast prefers TTG over every CTN codon, but GGT is already its
+	// preferred glycine codon.
+	if len(domestication.Edits) != 1 || domestication.Edits[0].Position != 7 || domestication.Edits[0].Replacement != "TTG" {
+		t.Errorf("Expected CTC7TTG, got %v", domestication.Edits)
+	}
+	if !domestication.IsDomesticated() {
+		t.Errorf("Expected no sites in %s", domestication.Sequence)
+	}
+}
+
+func TestDomesticateUnresolved(t *testing.T) {
+	MetTrp, err := enzyme.ParseSite("ATG^TGG")
+	if err != nil {
+		t.Fatalf("Failed to parse site: %s", err)
+	}
+	batch := enzyme.NewRestrictionBatch(MetTrp)
+
+	domestication, err := Domesticate("ATGTGGTAA", 0, &batch, EscherichiaColi)
+	if err != nil {
+		t.Fatalf("Failed to domesticate: %s", err)
+	}
+	if len(domestication.UnresolvedSites) != 1 || domestication.IsDomesticated() || len(domestication.Edits) != 0 {
+		t.Errorf("Expected the site over ATG TGG to be unresolved, got %+v", domestication)
+	}
+
+	if _, err := Domesticate("ATGTGGTAA", 4, &batch, EscherichiaColi); err == nil {
+		t.Errorf("Expected an error for an invalid frame")
+	}
+}
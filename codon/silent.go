@@ -0,0 +1,334 @@
+package codon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+// Options for finding sites that can be introduced into a coding sequence.
+type SilentSiteOptions struct {
+	// The number of amino acids each site may change. Zero only allows
+	// silent mutations. Stop codons are never introduced or removed.
+	MaxAminoAcidChanges int
+
+	// The construct the coding sequence is part of, e.g. a plasmid, and the
+	// position of the coding sequence in it. Sites are checked for
+	// uniqueness in the construct. If it is empty the coding sequence is
+	// checked on its own.
+	Construct   string
+	CodingStart int
+	IsCircular  bool
+
+	// The usage table used to choose between codons that need the same
+	// number of base changes. Optional.
+	Table *UsageTable
+}
+
+// A recognition site that can be created in a coding sequence.
+type SilentSite struct {
+	Enzyme               string
+	RecognitionSiteIndex int
+	Strand               constants.Strand
+
+	// The codon changes that create the site.
+	Edits            []CodonEdit
+	BaseChanges      int
+	AminoAcidChanges int
+
+	// The coding sequence with the edits applied.
+	Sequence string
+
+	// The number of sites of the enzyme in the construct after the edits,
+	// and true if the new site is the only one.
+	ConstructSites int
+	Unique         bool
+}
+
+/*
+Find every position where a site of an enzyme in the batch can be created
+in a coding sequence, read in the frame 0, 1 or 2, with codon changes that
+keep the protein, like the silent sites of NEBcutter. Set
+MaxAminoAcidChanges to also allow changes to the protein.
+
+Each site is created with the fewest amino acid changes and then the fewest
+base changes. Sites are ranked with sites that are unique in the construct
+first, then by the number of amino acid and base changes, then by enzyme
+name and position. Positions where a site already exists are not reported.
+*/
+func FindSilentSites(sequence string, frame int, batch *enzyme.RestrictionBatch, options SilentSiteOptions) ([]SilentSite, error) {
+	sequence = strings.ToUpper(sequence)
+	if _, err := Translate(sequence, frame); err != nil {
+		return nil, err
+	}
+
+	construct := strings.ToUpper(options.Construct)
+	codingStart := options.CodingStart
+	isCircular := options.IsCircular
+	if construct == "" {
+		construct, codingStart, isCircular = sequence, 0, false
+	}
+	if codingStart < 0 || codingStart+len(sequence) > len(construct) ||
+		construct[codingStart:codingStart+len(sequence)] != sequence {
+		return nil, fmt.Errorf("the coding sequence is not found at position %d of the construct", codingStart)
+	}
+
+	codingEnd := frame + (len(sequence)-frame)/3*3
+
+	sites := []SilentSite{}
+	for i := range batch.Enzymes {
+		ez := &batch.Enzymes[i]
+		if ez.Length == 0 {
+			continue
+		}
+
+		enzymeBatch := enzyme.NewRestrictionBatch(*ez)
+		constructSites := countSites(&enzymeBatch, construct, isCircular)
+
+		forwardSite := strings.ToUpper(ez.Site)
+		reverseSite := enzyme.ReverseComplementSite(ez.Site)
+		strands := []constants.Strand{constants.Watson}
+		if reverseSite != forwardSite {
+			strands = append(strands, constants.Crick)
+		}
+
+		for position := frame; position+ez.Length <= codingEnd; position++ {
+			for _, strand := range strands {
+				site := forwardSite
+				if strand == constants.Crick {
+					site = reverseSite
+				}
+
+				edits, ok := siteEdits(sequence, frame, position, site, options)
+				if !ok || len(edits) == 0 {
+					continue
+				}
+
+				silentSite := SilentSite{
+					Enzyme:               ez.Name,
+					RecognitionSiteIndex: position,
+					Strand:               strand,
+					Edits:                edits,
+					Sequence:             applyEdits(sequence, edits),
+				}
+				for _, edit := range edits {
+					silentSite.BaseChanges += hammingDistance(edit.Original, edit.Replacement)
+					if !edit.IsSynonymous() {
+						silentSite.AminoAcidChanges++
+					}
+				}
+
+				silentSite.ConstructSites = constructSites + countChangedSites(
+					&enzymeBatch, construct, codingStart, isCircular, edits)
+				silentSite.Unique = silentSite.ConstructSites == 1
+
+				sites = append(sites, silentSite)
+			}
+		}
+	}
+
+	sort.SliceStable(sites, func(i, j int) bool {
+		a, b := &sites[i], &sites[j]
+		switch {
+		case a.Unique != b.Unique:
+			return a.Unique
+		case a.AminoAcidChanges != b.AminoAcidChanges:
+			return a.AminoAcidChanges < b.AminoAcidChanges
+		case a.BaseChanges != b.BaseChanges:
+			return a.BaseChanges < b.BaseChanges
+		case a.Enzyme != b.Enzyme:
+			return a.Enzyme < b.Enzyme
+		default:
+			return a.RecognitionSiteIndex < b.RecognitionSiteIndex
+		}
+	})
+	return sites, nil
+}
+
+// A replacement for one codon that matches the site.
+type codonOption struct {
+	codon       string
+	aminoAcid   byte
+	baseChanges int
+}
+
+// Return the codon edits that create the site at the position with the
+// fewest amino acid changes, and then base changes, allowed by the options.
+// The second return value is false if the site cannot be created.
+func siteEdits(sequence string, frame int, position int, site string, options SilentSiteOptions) ([]CodonEdit, bool) {
+	firstCodon := (position - frame) / 3
+	lastCodon := (position + len(site) - 1 - frame) / 3
+
+	// The best synonymous and non-synonymous replacement of each codon.
+	codonCount := lastCodon - firstCodon + 1
+	synonymous := make([]*codonOption, codonCount)
+	changed := make([]*codonOption, codonCount)
+	for i := range synonymous {
+		codonStart := frame + (firstCodon+i)*3
+		synonymous[i], changed[i] = bestCodonOptions(sequence[codonStart:codonStart+3], codonStart, position, site, options.Table)
+	}
+
+	// Choose between the synonymous and non-synonymous replacement of each
+	// codon. Each set bit of the choice changes an amino acid.
+	var best []*codonOption
+	bestAminoAcidChanges, bestBaseChanges := 0, 0
+	for choice := 0; choice < 1<<codonCount; choice++ {
+		chosen := make([]*codonOption, codonCount)
+		aminoAcidChanges, baseChanges := 0, 0
+		for i := range chosen {
+			chosen[i] = synonymous[i]
+			if choice&(1<<i) != 0 {
+				chosen[i] = changed[i]
+				aminoAcidChanges++
+			}
+			if chosen[i] == nil {
+				chosen = nil
+				break
+			}
+			baseChanges += chosen[i].baseChanges
+		}
+		if chosen == nil || aminoAcidChanges > options.MaxAminoAcidChanges {
+			continue
+		}
+
+		if best == nil || aminoAcidChanges < bestAminoAcidChanges ||
+			(aminoAcidChanges == bestAminoAcidChanges && baseChanges < bestBaseChanges) {
+			best, bestAminoAcidChanges, bestBaseChanges = chosen, aminoAcidChanges, baseChanges
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+
+	edits := []CodonEdit{}
+	for i, option := range best {
+		codonStart := frame + (firstCodon+i)*3
+		original := sequence[codonStart : codonStart+3]
+		if option.codon == original {
+			continue
+		}
+		edits = append(edits, CodonEdit{
+			Position:             codonStart,
+			AminoAcid:            GeneticCode[original],
+			ReplacementAminoAcid: option.aminoAcid,
+			Original:             original,
+			Replacement:          option.codon,
+		})
+	}
+	return edits, true
+}
+
+// Return the synonymous and the non-synonymous replacement of the codon,
+// starting at codonStart, with the fewest base changes whose bases match the
+// site at the position. Ties are broken by the usage table, if given, and
+// then alphabetically. Either is nil if no replacement exists.
+func bestCodonOptions(original string, codonStart int, position int, site string, table *UsageTable) (*codonOption, *codonOption) {
+	originalAminoAcid := GeneticCode[original]
+
+	var synonymous, changed *codonOption
+	better := func(option *codonOption, current *codonOption) bool {
+		if current == nil || option.baseChanges != current.baseChanges {
+			return current == nil || option.baseChanges < current.baseChanges
+		}
+		if table != nil && table.Frequencies[option.codon] != table.Frequencies[current.codon] {
+			return table.Frequencies[option.codon] > table.Frequencies[current.codon]
+		}
+		return option.codon < current.codon
+	}
+
+	for codon, aminoAcid := range GeneticCode {
+		// Never introduce or remove a stop codon.
+		if (aminoAcid == '*') != (originalAminoAcid == '*') {
+			continue
+		}
+
+		matches := true
+		for j := 0; j < 3; j++ {
+			siteIndex := codonStart + j - position
+			if siteIndex >= 0 && siteIndex < len(site) && !enzyme.IUPACMatch(site[siteIndex], codon[j]) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+
+		option := &codonOption{codon, aminoAcid, hammingDistance(original, codon)}
+		if aminoAcid == originalAminoAcid {
+			if better(option, synonymous) {
+				synonymous = option
+			}
+		} else if better(option, changed) {
+			changed = option
+		}
+	}
+	return synonymous, changed
+}
+
+// Return the change in the number of sites found in the construct when the
+// edits are applied to the coding sequence starting at codingStart. Only the
+// sites that overlap an edited base can change.
+func countChangedSites(
+	batch *enzyme.RestrictionBatch,
+	construct string,
+	codingStart int,
+	isCircular bool,
+	edits []CodonEdit,
+) int {
+	first := codingStart + edits[0].Position
+	last := codingStart + edits[len(edits)-1].Position + 3
+	siteLength := batch.Enzymes[0].Length
+
+	// A circular window covering the whole construct is searched as the
+	// circular construct, so the sites spanning the origin are counted.
+	windowStart, windowEnd := first-siteLength+1, last+siteLength-1
+	searchCircular := false
+	if !isCircular {
+		windowStart, windowEnd = max(windowStart, 0), min(windowEnd, len(construct))
+	} else if windowEnd-windowStart >= len(construct) {
+		windowStart, windowEnd = 0, len(construct)
+		searchCircular = true
+	}
+
+	window := func(sequence string) string {
+		bases := make([]byte, windowEnd-windowStart)
+		for i := range bases {
+			bases[i] = sequence[((windowStart+i)%len(sequence)+len(sequence))%len(sequence)]
+		}
+		return string(bases)
+	}
+
+	editedConstruct := []byte(construct)
+	for _, edit := range edits {
+		copy(editedConstruct[codingStart+edit.Position:], edit.Replacement)
+	}
+
+	before := countSites(batch, window(construct), searchCircular)
+	after := countSites(batch, window(string(editedConstruct)), searchCircular)
+	return after - before
+}
+
+// Return the number of positions of the sequence with a site of the enzymes
+// in the batch. A non-palindromic degenerate site can match both strands at
+// one position, e.g. GKGCCC at GGGCCC, which is a single site.
+func countSites(batch *enzyme.RestrictionBatch, sequence string, isCircular bool) int {
+	positions := map[int]bool{}
+	for _, result := range batch.FindAll(sequence, isCircular) {
+		positions[result.RecognitionSiteIndex] = true
+	}
+	return len(positions)
+}
+
+func hammingDistance(a string, b string) int {
+	distance := 0
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			distance++
+		}
+	}
+	return distance
+}
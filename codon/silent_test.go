@@ -0,0 +1,164 @@
+package codon
+
+import (
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/db"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+func findSilentSite(sites []SilentSite, name string, position int) (SilentSite, bool) {
+	for _, site := range sites {
+		if site.Enzyme == name && site.RecognitionSiteIndex == position {
+			return site, true
+		}
+	}
+	return SilentSite{}, false
+}
+
+func TestFindSilentSites(t *testing.T) {
+	// GAA TTT can become the EcoRI site GAA TTC by changing the phenylalanine
+	// codon.
+	sequence := "ATG" + "GAA" + "TTT" + "AAA" + "TAA"
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["EcoRI"], db.Enzymes["BamHI"])
+
+	sites, err := FindSilentSites(sequence, 0, &batch, SilentSiteOptions{})
+	if err != nil {
+		t.Fatalf("Failed to find silent sites: %s", err)
+	}
+
+	site, ok := findSilentSite(sites, "EcoRI", 3)
+	if !ok {
+		t.Fatalf("Expected an EcoRI site at 3, got %+v", sites)
+	}
+	if len(site.Edits) != 1 || site.Edits[0].String() != "TTT6TTC (F)" || site.BaseChanges != 1 || site.AminoAcidChanges != 0 {
+		t.Errorf("Unexpected edits %v", site.Edits)
+	}
+	if site.Sequence != "ATGGAATTCAAATAA" || !site.Unique || site.ConstructSites != 1 {
+		t.Errorf("Expected a unique site in ATGGAATTCAAATAA, got %+v", site)
+	}
+	if sites[0].Enzyme != "EcoRI" || sites[0].RecognitionSiteIndex != 3 {
+		t.Errorf("Expected the single base change to be ranked first, got %+v", sites[0])
+	}
+
+	for _, site := range sites {
+		if site.AminoAcidChanges != 0 {
+			t.Errorf("Expected only silent sites, got %+v", site)
+		}
+		original, _ := Translate(sequence, 0)
+		edited, _ := Translate(site.Sequence, 0)
+		if original != edited {
+			t.Errorf("Expected %s to keep the protein %s, got %s", site.Enzyme, original, edited)
+		}
+	}
+}
+
+func TestFindSilentSitesAminoAcidChanges(t *testing.T) {
+	// GGA AAA can only become the BamHI site GGA TCC by changing the lysine
+	// to a serine.
+	sequence := "ATG" + "GGA" + "AAA" + "TAA"
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["BamHI"])
+
+	sites, err := FindSilentSites(sequence, 0, &batch, SilentSiteOptions{})
+	if err != nil {
+		t.Fatalf("Failed to find silent sites: %s", err)
+	}
+	if _, ok := findSilentSite(sites, "BamHI", 3); ok {
+		t.Errorf("Expected no silent BamHI site at 3")
+	}
+
+	sites, err = FindSilentSites(sequence, 0, &batch, SilentSiteOptions{MaxAminoAcidChanges: 1})
+	if err != nil {
+		t.Fatalf("Failed to find silent sites: %s", err)
+	}
+	site, ok := findSilentSite(sites, "BamHI", 3)
+	if !ok || site.AminoAcidChanges != 1 || site.Edits[0].String() != "AAA6TCC (K>S)" {
+		t.Errorf("Expected a BamHI site changing K to S, got %+v", site)
+	}
+}
+
+func TestFindSilentSitesConstruct(t *testing.T) {
+	sequence := "ATG" + "GAA" + "TTT" + "AAA" + "TAA"
+	construct := "GAATTC" + "CCCC" + sequence + "CCCC"
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["EcoRI"], db.Enzymes["SmaI"])
+
+	sites, err := FindSilentSites(sequence, 0, &batch, SilentSiteOptions{
+		Construct:   construct,
+		CodingStart: 10,
+		IsCircular:  true,
+		Table:       EscherichiaColi,
+	})
+	if err != nil {
+		t.Fatalf("Failed to find silent sites: %s", err)
+	}
+
+	site, ok := findSilentSite(sites, "EcoRI", 3)
+	if !ok || site.Unique || site.ConstructSites != 2 {
+		t.Errorf("Expected a second EcoRI site in the construct, got %+v", site)
+	}
+	for i := 1; i < len(sites); i++ {
+		if sites[i].Unique && !sites[i-1].Unique {
+			t.Errorf("Expected unique sites to be ranked before %+v", sites[i-1])
+		}
+	}
+
+	if _, err := FindSilentSites(sequence, 0, &batch, SilentSiteOptions{Construct: construct, CodingStart: 3}); err == nil {
+		t.Errorf("Expected an error when the coding sequence is not in the construct")
+	}
+}
+
+func TestFindSilentSitesAcrossOrigin(t *testing.T) {
+	// The circular construct is the coding sequence alone, with an EcoRI
+	// site spanning its origin. Making a site at 0 changes the TTC of the
+	// first codon to GAA and removes the site spanning the origin.
+	sequence := "TTC" + "TTT" + "CAA" + "GAA"
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["EcoRI"])
+
+	sites, err := FindSilentSites(sequence, 0, &batch, SilentSiteOptions{
+		Construct:           sequence,
+		IsCircular:          true,
+		MaxAminoAcidChanges: 1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to find silent sites: %s", err)
+	}
+
+	site, ok := findSilentSite(sites, "EcoRI", 0)
+	if !ok || site.Sequence != "GAATTCCAAGAA" || !site.Unique || site.ConstructSites != 1 {
+		t.Errorf("Expected the site at 0 to replace the site spanning the origin, got %+v", site)
+	}
+}
+
+func TestFindSilentSitesDegenerateBothStrands(t *testing.T) {
+	// GGGCCC matches the BmgI site GKGCCC on both strands. GGG CCA can
+	// become a second GGGCCC by changing the proline codon.
+	sequence := "ATG" + "GGG" + "CCC" + "AAA" + "GGG" + "CCA" + "TAA"
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["BmgI"])
+
+	sites, err := FindSilentSites(sequence, 0, &batch, SilentSiteOptions{})
+	if err != nil {
+		t.Fatalf("Failed to find silent sites: %s", err)
+	}
+
+	site, ok := findSilentSite(sites, "BmgI", 12)
+	if !ok || site.ConstructSites != 2 || site.Unique {
+		t.Errorf("Expected a second BmgI site, got %+v", site)
+	}
+}
+
+func TestFindSilentSitesStrand(t *testing.T) {
+	// BsaI, GGTCTC, read from the crick strand is GAGACC. GAG ACT can
+	// become GAG ACC by changing the threonine codon.
+	sequence := "ATG" + "GAG" + "ACT" + "TAA"
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["BsaI"])
+
+	sites, err := FindSilentSites(sequence, 0, &batch, SilentSiteOptions{})
+	if err != nil {
+		t.Fatalf("Failed to find silent sites: %s", err)
+	}
+	site, ok := findSilentSite(sites, "BsaI", 3)
+	if !ok || site.Strand != constants.Crick || site.BaseChanges != 1 {
+		t.Errorf("Expected a BsaI site on the crick strand at 3, got %+v", site)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Package codon translates coding sequences and holds the codon usage of
+common expression hosts. It is used to make silent, synonymous changes to
+coding sequences, e.g. to remove or introduce restriction sites.
+*/
+package codon
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The standard genetic code (NCBI translation table 1). Stop codons are
+// translated to *.
+var GeneticCode = map[string]byte{
+	"TTT": 'F', "TTC": 'F', "TTA": 'L', "TTG": 'L',
+	"CTT": 'L', "CTC": 'L', "CTA": 'L', "CTG": 'L',
+	"ATT": 'I', "ATC": 'I', "ATA": 'I', "ATG": 'M',
+	"GTT": 'V', "GTC": 'V', "GTA": 'V', "GTG": 'V',
+	"TCT": 'S', "TCC": 'S', "TCA": 'S', "TCG": 'S',
+	"CCT": 'P', "CCC": 'P', "CCA": 'P', "CCG": 'P',
+	"ACT": 'T', "ACC": 'T', "ACA": 'T', "ACG": 'T',
+	"GCT": 'A', "GCC": 'A', "GCA": 'A', "GCG": 'A',
+	"TAT": 'Y', "TAC": 'Y', "TAA": '*', "TAG": '*',
+	"CAT": 'H', "CAC": 'H', "CAA": 'Q', "CAG": 'Q',
+	"AAT": 'N', "AAC": 'N', "AAA": 'K', "AAG": 'K',
+	"GAT": 'D', "GAC": 'D', "GAA": 'E', "GAG": 'E',
+	"TGT": 'C', "TGC": 'C', "TGA": '*', "TGG": 'W',
+	"CGT": 'R', "CGC": 'R', "CGA": 'R', "CGG": 'R',
+	"AGT": 'S', "AGC": 'S', "AGA": 'R', "AGG": 'R',
+	"GGT": 'G', "GGC": 'G', "GGA": 'G', "GGG": 'G',
+}
+
+// Translate a coding sequence read in the frame, 0, 1 or 2, into amino acids.
+// Bases left over after the last full codon are ignored.
+func Translate(sequence string, frame int) (string, error) {
+	if frame < 0 || frame > 2 {
+		return "", fmt.Errorf("invalid reading frame %d, must be 0, 1 or 2", frame)
+	}
+
+	sequence = strings.ToUpper(sequence)
+	var protein strings.Builder
+	for i := frame; i+3 <= len(sequence); i += 3 {
+		aminoAcid, ok := GeneticCode[sequence[i:i+3]]
+		if !ok {
+			return "", fmt.Errorf("invalid codon %s at position %d", sequence[i:i+3], i)
+		}
+		protein.WriteByte(aminoAcid)
+	}
+	return protein.String(), nil
+}
+
+// The codon usage of an expression host, in codons per thousand.
+type UsageTable struct {
+	Host        string
+	Frequencies map[string]float64
+}
+
+// Create a usage table from the frequency of every one of the 64 codons.
+func NewUsageTable(host string, frequencies map[string]float64) (*UsageTable, error) {
+	table := &UsageTable{Host: host, Frequencies: map[string]float64{}}
+	for codon, frequency := range frequencies {
+		codon = strings.ToUpper(strings.ReplaceAll(codon, "U", "T"))
+		if _, ok := GeneticCode[codon]; !ok {
+			return nil, fmt.Errorf("invalid codon %s in the usage table of %s", codon, host)
+		}
+		if frequency < 0 {
+			return nil, fmt.Errorf("negative frequency for %s in the usage table of %s", codon, host)
+		}
+		table.Frequencies[codon] = frequency
+	}
+	if len(table.Frequencies) != len(GeneticCode) {
+		return nil, fmt.Errorf("the usage table of %s has %d of the 64 codons", host, len(table.Frequencies))
+	}
+	return table, nil
+}
+
+// Parse a usage table written as codon and frequency pairs separated by
+// white space, the layout of the Kazusa codon usage database.
+func mustParseUsageTable(host string, usage string) *UsageTable {
+	fields := strings.Fields(usage)
+	frequencies := map[string]float64{}
+	for i := 0; i+1 < len(fields); i += 2 {
+		frequency, err := strconv.ParseFloat(fields[i+1], 64)
+		if err != nil {
+			panic(fmt.Sprintf("invalid frequency %s in the usage table of %s", fields[i+1], host))
+		}
+		frequencies[fields[i]] = frequency
+	}
+
+	table, err := NewUsageTable(host, frequencies)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// Return the synonymous codons of the codon, the codons other than itself
+// that encode the same amino acid, most frequently used first.
+func (table *UsageTable) Synonyms(codon string) []string {
+	codon = strings.ToUpper(codon)
+	aminoAcid, ok := GeneticCode[codon]
+	if !ok {
+		return nil
+	}
+
+	synonyms := []string{}
+	for candidate, candidateAminoAcid := range GeneticCode {
+		if candidateAminoAcid == aminoAcid && candidate != codon {
+			synonyms = append(synonyms, candidate)
+		}
+	}
+
+	sort.Slice(synonyms, func(i, j int) bool {
+		if table.Frequencies[synonyms[i]] == table.Frequencies[synonyms[j]] {
+			return synonyms[i] < synonyms[j]
+		}
+		return table.Frequencies[synonyms[i]] > table.Frequencies[synonyms[j]]
+	})
+	return synonyms
+}
+
+// Return the frequency of the codon relative to the most frequently used
+// codon for the same amino acid, between 0 and 1.
+func (table *UsageTable) RelativeAdaptiveness(codon string) float64 {
+	codon = strings.ToUpper(codon)
+	aminoAcid, ok := GeneticCode[codon]
+	if !ok {
+		return 0
+	}
+
+	highest := 0.0
+	for candidate, candidateAminoAcid := range GeneticCode {
+		if candidateAminoAcid == aminoAcid {
+			highest = max(highest, table.Frequencies[candidate])
+		}
+	}
+	if highest == 0 {
+		return 0
+	}
+	return table.Frequencies[codon] / highest
+}
+
+var (
+	// Escherichia coli K-12, from the Kazusa codon usage database.
+	EscherichiaColi = mustParseUsageTable("Escherichia coli", `
+		TTT 22.1  TCT 10.4  TAT 17.5  TGT  5.2
+		TTC 16.0  TCC  9.1  TAC 12.2  TGC  6.1
+		TTA 14.3  TCA  8.9  TAA  2.0  TGA  1.0
+		TTG 13.0  TCG  8.5  TAG  0.3  TGG 13.9
+		CTT 11.9  CCT  7.5  CAT 12.5  CGT 21.0
+		CTC 10.2  CCC  5.4  CAC  9.3  CGC 22.0
+		CTA  4.2  CCA  8.6  CAA 14.6  CGA  4.3
+		CTG 48.4  CCG 20.9  CAG 28.4  CGG  5.9
+		ATT 29.8  ACT 10.3  AAT 20.6  AGT  9.9
+		ATC 23.7  ACC 22.0  AAC 21.4  AGC 15.2
+		ATA  6.8  ACA  9.3  AAA 35.3  AGA  3.6
+		ATG 26.4  ACG 13.7  AAG 12.4  AGG  2.1
+		GTT 19.8  GCT 17.1  GAT 32.7  GGT 25.5
+		GTC 14.3  GCC 24.2  GAC 19.2  GGC 27.1
+		GTA 11.6  GCA 21.2  GAA 39.1  GGA  9.5
+		GTG 24.4  GCG 30.1  GAG 18.7  GGG 11.3`)
+
+	// Saccharomyces cerevisiae, from the Kazusa codon usage database.
+	SaccharomycesCerevisiae = mustParseUsageTable("Saccharomyces cerevisiae", `
+		TTT 26.1  TCT 23.5  TAT 18.8  TGT  8.1
+		TTC 18.4  TCC 14.2  TAC 14.8  TGC  4.8
+		TTA 26.2  TCA 18.7  TAA  1.1  TGA  0.7
+		TTG 27.2  TCG  8.6  TAG  0.5  TGG 10.4
+		CTT 12.3  CCT 13.5  CAT 13.6  CGT  6.4
+		CTC  5.4  CCC  6.8  CAC  7.8  CGC  2.6
+		CTA 13.4  CCA 18.3  CAA 27.3  CGA  3.0
+		CTG 10.5  CCG  5.3  CAG 12.1  CGG  1.7
+		ATT 30.1  ACT 20.3  AAT 35.7  AGT 14.2
+		ATC 17.2  ACC 12.7  AAC 24.8  AGC  9.8
+		ATA 17.8  ACA 17.8  AAA 41.9  AGA 21.3
+		ATG 20.9  ACG  8.0  AAG 30.8  AGG  9.2
+		GTT 22.1  GCT 21.2  GAT 37.6  GGT 23.9
+		GTC 11.8  GCC 12.6  GAC 20.2  GGC  9.8
+		GTA 11.8  GCA 16.2  GAA 45.6  GGA 10.9
+		GTG 10.8  GCG  6.2  GAG 19.2  GGG  6.0`)
+
+	// Homo sapiens, from the Kazusa codon usage database.
+	HomoSapiens = mustParseUsageTable("Homo sapiens", `
+		TTT 17.6  TCT 15.2  TAT 12.2  TGT 10.6
+		TTC 20.3  TCC 17.7  TAC 15.3  TGC 12.6
+		TTA  7.7  TCA 12.2  TAA  1.0  TGA  1.6
+		TTG 12.9  TCG  4.4  TAG  0.8  TGG 13.2
+		CTT 13.2  CCT 17.5  CAT 10.9  CGT  4.5
+		CTC 19.6  CCC 19.8  CAC 15.1  CGC 10.4
+		CTA  7.2  CCA 16.9  CAA 12.3  CGA  6.2
+		CTG 39.6  CCG  6.9  CAG 34.2  CGG 11.4
+		ATT 16.0  ACT 13.1  AAT 17.0  AGT 12.1
+		ATC 20.8  ACC 18.9  AAC 19.1  AGC 19.5
+		ATA  7.5  ACA 15.1  AAA 24.4  AGA 12.2
+		ATG 22.0  ACG  6.1  AAG 31.9  AGG 12.0
+		GTT 11.0  GCT 18.4  GAT 21.8  GGT 10.8
+		GTC 14.5  GCC 27.7  GAC 25.1  GGC 22.2
+		GTA  7.1  GCA 15.8  GAA 29.0  GGA 16.5
+		GTG 28.1  GCG  7.4  GAG 39.6  GGG 16.5`)
+)
+
+// The usage tables of the built in hosts by name.
+var UsageTables = map[string]*UsageTable{
+	EscherichiaColi.Host:         EscherichiaColi,
+	SaccharomycesCerevisiae.Host: SaccharomycesCerevisiae,
+	HomoSapiens.Host:             HomoSapiens,
+}
@@ -0,0 +1,55 @@
+package codon
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTranslate(t *testing.T) {
+	protein, err := Translate("ATGGGTCTCAAATAA", 0)
+	if err != nil || protein != "MGLK*" {
+		t.Errorf("Expected MGLK*, got %s, %v", protein, err)
+	}
+
+	protein, err = Translate("CCATGGGTCTCAA", 2)
+	if err != nil || protein != "MGL" {
+		t.Errorf("Expected MGL in frame 2, got %s, %v", protein, err)
+	}
+
+	if _, err := Translate("ATGNNN", 0); err == nil {
+		t.Errorf("Expected an error for an invalid codon")
+	}
+	if _, err := Translate("ATG", 3); err == nil {
+		t.Errorf("Expected an error for an invalid frame")
+	}
+}
+
+func TestUsageTables(t *testing.T) {
+	for host, table := range UsageTables {
+		if len(table.Frequencies) != 64 {
+			t.Errorf("Expected 64 codons for %s, got %d", host, len(table.Frequencies))
+		}
+	}
+
+	if synonyms := EscherichiaColi.Synonyms("GGT"); !reflect.DeepEqual(synonyms, []string{"GGC", "GGG", "GGA"}) {
+		t.Errorf("Expected synonyms [GGC GGG GGA], got %v", synonyms)
+	}
+	if synonyms := EscherichiaColi.Synonyms("ATG"); len(synonyms) != 0 {
+		t.Errorf("Expected no synonyms for ATG, got %v", synonyms)
+	}
+
+	if adaptiveness := EscherichiaColi.RelativeAdaptiveness("CTG"); adaptiveness != 1 {
+		t.Errorf("Expected CTG to be the preferred leucine codon, got %f", adaptiveness)
+	}
+	if adaptiveness := SaccharomycesCerevisiae.RelativeAdaptiveness("CTG"); math.Abs(adaptiveness-10.5/27.2) > 1e-9 {
+		t.Errorf("Expected CTG to be rare in yeast, got %f", adaptiveness)
+	}
+
+	if _, err := NewUsageTable("partial", map[string]float64{"ATG": 1}); err == nil {
+		t.Errorf("Expected an error for a table missing codons")
+	}
+	if _, err := NewUsageTable("invalid", map[string]float64{"XYZ": 1}); err == nil {
+		t.Errorf("Expected an error for an invalid codon")
+	}
+}
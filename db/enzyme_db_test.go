@@ -0,0 +1,69 @@
+package db
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+// Search a random sequence about the size of a bacterial genome for the
+// sites of every enzyme in the database with a site of at least six bases.
+// Shorter sites, like the single base sites of the methylation dependent
+// enzymes, match so often that building the results dominates the search.
+func BenchmarkSearchAllEnzymes(b *testing.B) {
+	random := rand.New(rand.NewSource(1))
+	genome := make([]byte, 5_000_000)
+	for i := range genome {
+		genome[i] = "ACGT"[random.Intn(4)]
+	}
+
+	batch := enzyme.NewRestrictionBatch()
+	for _, ez := range allEnzymes() {
+		if ez.Length >= 6 {
+			batch.Add(ez)
+		}
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		batch.FindAll(string(genome), true)
+	}
+}
+
+// The database does not carry the REBASE methylation lines yet, so these use
+// the real entries to check the fallback to the curated sensitivity table.
+func TestMethylationStatusOfDatabaseEnzymes(t *testing.T) {
+	XbaI := Enzymes["XbaI"]
+	if status := XbaI.GetMethylationStatus("AAATCTAGATCAAA", 3, enzyme.DH5Alpha, false); status != enzyme.Blocked {
+		t.Errorf("Expected XbaI overlapping dam to be blocked in DH5alpha, got %s", status)
+	}
+	if status := XbaI.GetMethylationStatus("AAATCTAGATCAAA", 3, enzyme.JM110, false); status != enzyme.Cleavable {
+		t.Errorf("Expected XbaI to be cleavable in a dam- strain, got %s", status)
+	}
+	if status := XbaI.GetMethylationStatus("AAATCTAGAAAA", 3, enzyme.DH5Alpha, false); status != enzyme.Cleavable {
+		t.Errorf("Expected XbaI without dam to be cleavable, got %s", status)
+	}
+
+	ClaI := Enzymes["ClaI"]
+	if status := ClaI.GetMethylationStatus("AAGATCGATAAA", 3, enzyme.DH5Alpha, false); status != enzyme.Blocked {
+		t.Errorf("Expected ClaI overlapping dam to be blocked in DH5alpha, got %s", status)
+	}
+}
+
+func TestTypeIIBEnzyme(t *testing.T) {
+	BaeI, ok := Enzymes["BaeI"]
+	if !ok {
+		t.Skip("BaeI is not in the database. Regenerate it with the script in the script directory to include the Type IIB enzymes.")
+	}
+
+	if BaeI.NumberOfCuts != enzyme.FourCuts {
+		t.Errorf("Expected BaeI to have 4 cuts, got %d", BaeI.NumberOfCuts)
+	}
+	if BaeI.FivePrimeCutSite != -11 || BaeI.ThreePrimeCutSite != -16 {
+		t.Errorf("Expected BaeI first cut sites -11/-16, got %d/%d", BaeI.FivePrimeCutSite, BaeI.ThreePrimeCutSite)
+	}
+	if BaeI.FivePrimeCutSite2 != 23 || BaeI.ThreePrimeCutSite2 != 18 {
+		t.Errorf("Expected BaeI second cut sites 23/18, got %d/%d", BaeI.FivePrimeCutSite2, BaeI.ThreePrimeCutSite2)
+	}
+}
@@ -0,0 +1,379 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+// A predicate selecting enzymes from the database.
+type Query func(ez enzyme.Enzyme) bool
+
+// Return a query matching enzymes that match both queries.
+func (query Query) And(other Query) Query {
+	return func(ez enzyme.Enzyme) bool {
+		return query(ez) && other(ez)
+	}
+}
+
+// Return a query matching enzymes that match either query.
+func (query Query) Or(other Query) Query {
+	return func(ez enzyme.Enzyme) bool {
+		return query(ez) || other(ez)
+	}
+}
+
+// Return a query matching enzymes that do not match the query.
+func (query Query) Not() Query {
+	return func(ez enzyme.Enzyme) bool {
+		return !query(ez)
+	}
+}
+
+// Return a restriction batch of the enzymes in the database matching the
+// query, sorted by name.
+func Select(query Query) enzyme.RestrictionBatch {
+	selected := []enzyme.Enzyme{}
+	for _, ez := range allEnzymes() {
+		if query(ez) {
+			selected = append(selected, ez)
+		}
+	}
+	return enzyme.NewRestrictionBatch(selected...)
+}
+
+// Parse the query and return a restriction batch of the enzymes in the
+// database matching it, sorted by name. See ParseQuery for the syntax.
+func SelectWhere(query string) (enzyme.RestrictionBatch, error) {
+	parsedQuery, err := ParseQuery(query)
+	if err != nil {
+		return enzyme.RestrictionBatch{}, err
+	}
+	return Select(parsedQuery), nil
+}
+
+// The fields that can be queried and the operators they support.
+var queryFields = map[string][]string{
+	"name":         {"=", "!=", "IN"},
+	"site":         {"=", "!=", "IN"},
+	"site_len":     {"=", "!=", "<", "<=", ">", ">=", "IN"},
+	"cut":          {"=", "!=", "IN"},
+	"overhang":     {"=", "!=", "IN"},
+	"overhang_len": {"=", "!=", "<", "<=", ">", ">=", "IN"},
+	"ncuts":        {"=", "!=", "<", "<=", ">", ">=", "IN"},
+	"supplier":     {"=", "!=", "IN"},
+}
+
+// The fields that are true or false and are written on their own.
+var queryFlags = map[string]Query{
+	"commercial": func(ez enzyme.Enzyme) bool {
+		return IsCommerciallyAvailable(ez.Name)
+	},
+	"degenerate": func(ez enzyme.Enzyme) bool {
+		return enzyme.IsDegenerate(ez.Site)
+	},
+	"palindromic": func(ez enzyme.Enzyme) bool {
+		return strings.EqualFold(ez.Site, enzyme.ReverseComplementSite(ez.Site))
+	},
+}
+
+// Return a query comparing a field of the enzymes to one or more values.
+// The fields are:
+//
+//	name         - the enzyme name, e.g. EcoRI
+//	site         - the recognition site, e.g. GAATTC
+//	site_len     - the length of the recognition site
+//	cut          - the cut type, sticky or blunt
+//	overhang     - the overhang type, 5', 3', blunt or unknown
+//	overhang_len - the number of bases in the overhang
+//	ncuts        - the number of strand cuts, 0 if unknown, 2 or 4
+//	supplier     - the id of a supplier selling the enzyme, e.g. N
+//
+// The operators are =, !=, <, <=, >, >= for the numeric fields and IN, which
+// matches any of the values.
+func Compare(field string, operator string, values ...string) (Query, error) {
+	field = strings.ToLower(field)
+	operator = strings.ToUpper(operator)
+
+	operators, ok := queryFields[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown query field: %s", field)
+	}
+	if !slices.Contains(operators, operator) {
+		return nil, fmt.Errorf("operator %s is not supported for %s", operator, field)
+	}
+	if operator == "IN" && len(values) == 0 {
+		return nil, fmt.Errorf("operator IN needs one or more values")
+	}
+	if operator != "IN" && len(values) != 1 {
+		return nil, fmt.Errorf("operator %s needs one value", operator)
+	}
+
+	switch field {
+	case "site_len", "overhang_len", "ncuts":
+		numbers := make([]int, len(values))
+		for i, value := range values {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be compared to a number, got %s", field, value)
+			}
+			numbers[i] = number
+		}
+
+		fieldValue := func(ez enzyme.Enzyme) int {
+			switch field {
+			case "site_len":
+				return ez.Length
+			case "overhang_len":
+				if ez.OverhangLength < 0 {
+					return -ez.OverhangLength
+				}
+				return ez.OverhangLength
+			default:
+				return int(ez.NumberOfCuts)
+			}
+		}
+		return func(ez enzyme.Enzyme) bool {
+			return compareInts(fieldValue(ez), operator, numbers)
+		}, nil
+
+	case "supplier":
+		return func(ez enzyme.Enzyme) bool {
+			suppliers := EnzymeSuppliers(ez.Name)
+			matched := slices.ContainsFunc(values, func(value string) bool {
+				return slices.Contains(suppliers, value)
+			})
+			return matched == (operator != "!=")
+		}, nil
+	}
+
+	fieldValue := func(ez enzyme.Enzyme) string {
+		switch field {
+		case "name":
+			return ez.Name
+		case "site":
+			return strings.ToUpper(ez.Site)
+		case "cut":
+			return string(ez.CutType)
+		default:
+			return string(ez.OverhangType())
+		}
+	}
+	return func(ez enzyme.Enzyme) bool {
+		matched := slices.ContainsFunc(values, func(value string) bool {
+			return strings.EqualFold(fieldValue(ez), value)
+		})
+		return matched == (operator != "!=")
+	}, nil
+}
+
+func compareInts(value int, operator string, numbers []int) bool {
+	switch operator {
+	case "=":
+		return value == numbers[0]
+	case "!=":
+		return value != numbers[0]
+	case "<":
+		return value < numbers[0]
+	case "<=":
+		return value <= numbers[0]
+	case ">":
+		return value > numbers[0]
+	case ">=":
+		return value >= numbers[0]
+	default:
+		return slices.Contains(numbers, value)
+	}
+}
+
+// Parse a query string such as
+//
+//	site_len>=6 AND cut=sticky AND overhang=5' AND overhang_len=4 AND supplier IN (N,B) AND commercial
+//
+// Comparisons are written as field, operator and value, see Compare for the
+// fields and operators. The flags commercial, degenerate and palindromic are
+// written on their own. Conditions are combined with AND, OR and NOT, where
+// NOT binds tightest and AND binds tighter than OR, and can be grouped with
+// parentheses. Keywords and fields are case insensitive.
+func ParseQuery(query string) (Query, error) {
+	tokens, err := tokenizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &queryParser{tokens: tokens}
+	parsedQuery, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.position < len(parser.tokens) {
+		return nil, fmt.Errorf("unexpected %q in query", parser.tokens[parser.position])
+	}
+	return parsedQuery, nil
+}
+
+// Split a query into words, operators and punctuation.
+func tokenizeQuery(query string) ([]string, error) {
+	isWordChar := func(char rune) bool {
+		return unicode.IsLetter(char) || unicode.IsDigit(char) || strings.ContainsRune("_'-", char)
+	}
+
+	tokens := []string{}
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		char := runes[i]
+		switch {
+		case unicode.IsSpace(char):
+			i++
+		case strings.ContainsRune("(),", char):
+			tokens = append(tokens, string(char))
+			i++
+		case strings.ContainsRune("<>!=", char):
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, string(runes[i:i+2]))
+				i += 2
+			} else if char == '!' {
+				return nil, fmt.Errorf("unexpected ! in query")
+			} else {
+				tokens = append(tokens, string(char))
+				i++
+			}
+		case isWordChar(char):
+			start := i
+			for i < len(runes) && isWordChar(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			return nil, fmt.Errorf("unexpected %q in query", char)
+		}
+	}
+	return tokens, nil
+}
+
+// A recursive descent parser over the tokens of a query.
+type queryParser struct {
+	tokens   []string
+	position int
+}
+
+func (parser *queryParser) peek() string {
+	if parser.position < len(parser.tokens) {
+		return parser.tokens[parser.position]
+	}
+	return ""
+}
+
+func (parser *queryParser) next() string {
+	token := parser.peek()
+	parser.position++
+	return token
+}
+
+func (parser *queryParser) isKeyword(keyword string) bool {
+	return strings.EqualFold(parser.peek(), keyword)
+}
+
+func (parser *queryParser) parseOr() (Query, error) {
+	query, err := parser.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for parser.isKeyword("OR") {
+		parser.next()
+		other, err := parser.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		query = query.Or(other)
+	}
+	return query, nil
+}
+
+func (parser *queryParser) parseAnd() (Query, error) {
+	query, err := parser.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for parser.isKeyword("AND") {
+		parser.next()
+		other, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		query = query.And(other)
+	}
+	return query, nil
+}
+
+func (parser *queryParser) parseNot() (Query, error) {
+	if parser.isKeyword("NOT") {
+		parser.next()
+		query, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return query.Not(), nil
+	}
+	return parser.parseCondition()
+}
+
+func (parser *queryParser) parseCondition() (Query, error) {
+	token := parser.next()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of query")
+
+	case token == "(":
+		query, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if parser.next() != ")" {
+			return nil, fmt.Errorf("missing ) in query")
+		}
+		return query, nil
+	}
+
+	field := strings.ToLower(token)
+	if flag, ok := queryFlags[field]; ok {
+		return flag, nil
+	}
+	if _, ok := queryFields[field]; !ok {
+		return nil, fmt.Errorf("unknown query field: %s", token)
+	}
+
+	operator := strings.ToUpper(parser.next())
+	if operator != "IN" {
+		value := parser.next()
+		if value == "" || strings.Contains("(),", value) {
+			return nil, fmt.Errorf("expected a value after %s %s", field, operator)
+		}
+		return Compare(field, operator, value)
+	}
+
+	if parser.next() != "(" {
+		return nil, fmt.Errorf("IN must be followed by a list of values in parentheses")
+	}
+	values := []string{}
+	for {
+		value := parser.next()
+		if value == "" || value == "(" || value == ")" || value == "," {
+			return nil, fmt.Errorf("expected a value in the IN list of %s", field)
+		}
+		values = append(values, value)
+
+		separator := parser.next()
+		if separator == ")" {
+			break
+		}
+		if separator != "," {
+			return nil, fmt.Errorf("expected , or ) in the IN list of %s", field)
+		}
+	}
+	return Compare(field, operator, values...)
+}
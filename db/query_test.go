@@ -0,0 +1,87 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestSelectWhere(t *testing.T) {
+	batch, err := SelectWhere("site_len>=6 AND cut=sticky AND overhang=5' AND overhang_len=4 AND supplier IN (N,B) AND commercial")
+	if err != nil {
+		t.Fatalf("Error selecting: %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, ez := range batch.Enzymes {
+		names[ez.Name] = true
+
+		if ez.Length < 6 || ez.OverhangLength != -4 {
+			t.Errorf("Unexpected enzyme %s with site %s and overhang %d", ez.Name, ez.Site, ez.OverhangLength)
+		}
+	}
+
+	for _, name := range []string{"EcoRI", "BamHI", "BsaI", "XbaI"} {
+		if !names[name] {
+			t.Errorf("Expected %s to be selected", name)
+		}
+	}
+	for _, name := range []string{"SmaI", "PstI", "ApaI", "DpnI"} {
+		if names[name] {
+			t.Errorf("Expected %s not to be selected", name)
+		}
+	}
+
+	for i := 1; i < len(batch.Enzymes); i++ {
+		if batch.Enzymes[i-1].Name > batch.Enzymes[i].Name {
+			t.Errorf("Expected the enzymes to be sorted by name")
+			break
+		}
+	}
+}
+
+func TestSelectWhereOrNot(t *testing.T) {
+	batch, err := SelectWhere("(name = EcoRI OR name=SmaI) and not cut = blunt")
+	if err != nil {
+		t.Fatalf("Error selecting: %v", err)
+	}
+	if len(batch.Enzymes) != 1 || batch.Enzymes[0].Name != "EcoRI" {
+		t.Errorf("Expected only EcoRI, got %v", batch.Enzymes)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	query, err := Compare("site", "IN", "GAATTC", "CCCGGG")
+	if err != nil {
+		t.Fatalf("Error creating query: %v", err)
+	}
+	if !query(Enzymes["EcoRI"]) || !query(Enzymes["SmaI"]) || query(Enzymes["BamHI"]) {
+		t.Errorf("Expected the query to match EcoRI and SmaI only")
+	}
+
+	batch := Select(query.And(queryFlags["commercial"]).And(queryFlags["palindromic"]))
+	for _, ez := range batch.Enzymes {
+		if ez.Site != "GAATTC" && ez.Site != "CCCGGG" {
+			t.Errorf("Unexpected enzyme %s with site %s", ez.Name, ez.Site)
+		}
+		if !IsCommerciallyAvailable(ez.Name) {
+			t.Errorf("Expected %s to be commercially available", ez.Name)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"color = red",
+		"cut > sticky",
+		"site_len >= six",
+		"site_len >=",
+		"supplier IN (N,",
+		"(commercial",
+		"commercial commercial",
+		"name ! EcoRI",
+	} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("Expected an error parsing %q", query)
+		}
+	}
+}
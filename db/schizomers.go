@@ -0,0 +1,122 @@
+package db
+
+import (
+	"sort"
+
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+// A mapping of enzyme name to the ids of the suppliers that sell it.
+var suppliersByEnzyme = buildSuppliersByEnzyme()
+
+func buildSuppliersByEnzyme() map[string][]string {
+	suppliersByEnzyme := map[string][]string{}
+	for _, supplier := range Suppliers {
+		for _, enzymeName := range supplier.Enzymes {
+			suppliersByEnzyme[enzymeName] = append(suppliersByEnzyme[enzymeName], supplier.Id)
+		}
+	}
+	return suppliersByEnzyme
+}
+
+// Return the ids of the suppliers that sell the enzyme, e.g. "N" for New
+// England Biolabs. The result is empty if the enzyme is not commercially
+// available.
+func EnzymeSuppliers(enzymeName string) []string {
+	return suppliersByEnzyme[enzymeName]
+}
+
+// Return true if at least one supplier sells the enzyme.
+func IsCommerciallyAvailable(enzymeName string) bool {
+	return len(suppliersByEnzyme[enzymeName]) > 0
+}
+
+// Return all the enzymes in the database as a slice sorted by name.
+func allEnzymes() []enzyme.Enzyme {
+	enzymes := make([]enzyme.Enzyme, 0, len(Enzymes))
+	for _, enzyme := range Enzymes {
+		enzymes = append(enzymes, enzyme)
+	}
+
+	sort.Slice(enzymes, func(i, j int) bool {
+		return enzymes[i].Name < enzymes[j].Name
+	})
+	return enzymes
+}
+
+// Return the enzymes in the database that recognize the same site as the
+// enzyme and cut it at the same positions.
+func Isoschizomers(ez enzyme.Enzyme) []enzyme.Enzyme {
+	return enzyme.Isoschizomers(ez, schizomerCandidates(ez))
+}
+
+// Return the enzymes in the database that recognize the same site as the
+// enzyme but cut it at different positions.
+func Neoschizomers(ez enzyme.Enzyme) []enzyme.Enzyme {
+	return enzyme.Neoschizomers(ez, schizomerCandidates(ez))
+}
+
+// Return the enzymes in the database REBASE lists as recognizing the same
+// site as the enzyme, sorted by name. An enzyme without a REBASE list, e.g.
+// one parsed from a site, is compared with every enzyme in the database.
+func schizomerCandidates(ez enzyme.Enzyme) []enzyme.Enzyme {
+	if len(ez.Isoschizomers) == 0 {
+		return allEnzymes()
+	}
+
+	candidates := []enzyme.Enzyme{}
+	for _, name := range ez.Isoschizomers {
+		if candidate, ok := Enzymes[name]; ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates
+}
+
+// Return the commercially available isoschizomers of the enzyme. These
+// enzymes produce the same ends and can be ordered as a substitute.
+func CommercialEquivalents(ez enzyme.Enzyme) []enzyme.Enzyme {
+	equivalents := []enzyme.Enzyme{}
+	for _, isoschizomer := range Isoschizomers(ez) {
+		if IsCommerciallyAvailable(isoschizomer.Name) {
+			equivalents = append(equivalents, isoschizomer)
+		}
+	}
+	return equivalents
+}
+
+// True if the database was generated with the REBASE prototype and
+// isoschizomer lines. Without them an enzyme with no prototype cannot be told
+// apart from an enzyme whose prototype is unknown.
+var hasSchizomerData = checkSchizomerData()
+
+func checkSchizomerData() bool {
+	for _, ez := range Enzymes {
+		if ez.Prototype != "" || len(ez.Isoschizomers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the prototype of the enzyme, the first enzyme characterized with
+// its recognition site, as recorded by REBASE. REBASE records no prototype
+// for an enzyme that is itself the prototype, so the enzyme is returned.
+// False is returned if the prototype is not in the database, or if it is
+// unknown because neither the enzyme nor the database has REBASE data.
+func Prototype(ez enzyme.Enzyme) (enzyme.Enzyme, bool) {
+	if ez.Prototype == "" {
+		return ez, hasSchizomerData || len(ez.Isoschizomers) > 0
+	}
+	prototype, ok := Enzymes[ez.Prototype]
+	return prototype, ok
+}
+
+// Return the enzymes in the database whose ends can be ligated to the ends
+// left by the enzyme, e.g. BglII and BclI for BamHI.
+func CompatibleEnds(ez enzyme.Enzyme) []enzyme.CompatibleEnd {
+	return enzyme.CompatibleEnds(ez, allEnzymes())
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+func enzymeNames(enzymes []enzyme.Enzyme) []string {
+	names := []string{}
+	for _, enzyme := range enzymes {
+		names = append(names, enzyme.Name)
+	}
+	return names
+}
+
+func TestIsoschizomers(t *testing.T) {
+	isoschizomers := enzymeNames(Isoschizomers(Enzymes["BsaI"]))
+	expected := []string{"Bso31I", "BspTNI", "Eco31I"}
+	if !reflect.DeepEqual(isoschizomers, expected) {
+		t.Errorf("Expected isoschizomers %v, got %v", expected, isoschizomers)
+	}
+}
+
+func TestNeoschizomers(t *testing.T) {
+	neoschizomers := enzymeNames(Neoschizomers(Enzymes["SmaI"]))
+	expected := []string{"Cfr9I", "TspMI", "XmaI"}
+	if !reflect.DeepEqual(neoschizomers, expected) {
+		t.Errorf("Expected neoschizomers %v, got %v", expected, neoschizomers)
+	}
+}
+
+func TestCommercialEquivalents(t *testing.T) {
+	if !IsCommerciallyAvailable("BsaI") {
+		t.Errorf("Expected BsaI to be commercially available")
+	}
+
+	for _, equivalent := range CommercialEquivalents(Enzymes["BsaI"]) {
+		if len(EnzymeSuppliers(equivalent.Name)) == 0 {
+			t.Errorf("Expected %s to have a supplier", equivalent.Name)
+		}
+	}
+
+	equivalents := enzymeNames(CommercialEquivalents(Enzymes["BsaI"]))
+	if len(equivalents) == 0 || equivalents[0] != "Bso31I" {
+		t.Errorf("Expected Bso31I to be a commercial equivalent of BsaI, got %v", equivalents)
+	}
+}
+
+func TestPrototype(t *testing.T) {
+	// The prototype and isoschizomers of BsaI and XmaIII as given by REBASE.
+	BsaI := Enzymes["BsaI"]
+	BsaI.Prototype = "Eco31I"
+	if prototype, ok := Prototype(BsaI); !ok || prototype.Name != "Eco31I" {
+		t.Errorf("Expected Eco31I to be the prototype of BsaI, got %s", prototype.Name)
+	}
+
+	AaaI := enzyme.Enzyme{Name: "AaaI", Site: "CGGCCG", Length: 6, Prototype: "XmaIII"}
+	if prototype, ok := Prototype(AaaI); !ok || prototype.Name != "XmaIII" {
+		t.Errorf("Expected XmaIII to be the prototype of AaaI, got %s", prototype.Name)
+	}
+
+	// A prototype has no prototype recorded.
+	Eco31I := Enzymes["Eco31I"]
+	Eco31I.Isoschizomers = []string{"BsaI", "Bso31I", "BspTNI"}
+	if prototype, ok := Prototype(Eco31I); !ok || prototype.Name != "Eco31I" {
+		t.Errorf("Expected Eco31I to be its own prototype, got %s", prototype.Name)
+	}
+
+	// BamHI, the prototype of AacLI, is in the database but AacLI is not. The
+	// reverse is reported as missing.
+	AacLI := enzyme.Enzyme{Name: "AacLI", Site: "GGATCC", Length: 6, Prototype: "BamHI"}
+	if prototype, ok := Prototype(AacLI); !ok || prototype.Name != "BamHI" {
+		t.Errorf("Expected BamHI to be the prototype of AacLI, got %s", prototype.Name)
+	}
+	if _, ok := Prototype(enzyme.Enzyme{Name: "Missing", Prototype: "AacLI"}); ok {
+		t.Errorf("Expected a prototype missing from the database not to be found")
+	}
+}
+
+func TestIsoschizomersFromRebase(t *testing.T) {
+	// Only enzymes REBASE lists are candidates. Names missing from the
+	// database are skipped.
+	BsaI := Enzymes["BsaI"]
+	BsaI.Isoschizomers = []string{"Eco31I", "Bso31I", "AacLI"}
+
+	isoschizomers := enzymeNames(Isoschizomers(BsaI))
+	expected := []string{"Bso31I", "Eco31I"}
+	if !reflect.DeepEqual(isoschizomers, expected) {
+		t.Errorf("Expected isoschizomers %v, got %v", expected, isoschizomers)
+	}
+
+	SmaI := Enzymes["SmaI"]
+	SmaI.Isoschizomers = []string{"XmaI", "Cfr9I"}
+	neoschizomers := enzymeNames(Neoschizomers(SmaI))
+	expected = []string{"Cfr9I", "XmaI"}
+	if !reflect.DeepEqual(neoschizomers, expected) {
+		t.Errorf("Expected neoschizomers %v, got %v", expected, neoschizomers)
+	}
+}
+
+func TestSchizomersOfDatabaseEnzymes(t *testing.T) {
+	// The database is compared by site until it is regenerated with the
+	// REBASE isoschizomer lines, so these use the real entries.
+	if neoschizomers := enzymeNames(Neoschizomers(Enzymes["Acc65I"])); !reflect.DeepEqual(neoschizomers, []string{"KpnI"}) {
+		t.Errorf("Expected KpnI to be the neoschizomer of Acc65I, got %v", neoschizomers)
+	}
+	// The blunt Ecl136II and its isoschizomers against the sticky SacI and
+	// its isoschizomers.
+	if neoschizomers := enzymeNames(Neoschizomers(Enzymes["SacI"])); !reflect.DeepEqual(neoschizomers, []string{"Ecl136II", "Eco53kI", "EcoICRI"}) {
+		t.Errorf("Expected Ecl136II to be a neoschizomer of SacI, got %v", neoschizomers)
+	}
+	if neoschizomers := enzymeNames(Neoschizomers(Enzymes["Ecl136II"])); !reflect.DeepEqual(neoschizomers, []string{"Psp124BI", "SacI", "SstI"}) {
+		t.Errorf("Expected SacI to be a neoschizomer of Ecl136II, got %v", neoschizomers)
+	}
+
+	// No other enzyme in the database recognizes GAATTC.
+	if isoschizomers := Isoschizomers(Enzymes["EcoRI"]); len(isoschizomers) != 0 {
+		t.Errorf("Expected EcoRI to have no isoschizomers in the database, got %v", enzymeNames(isoschizomers))
+	}
+
+	// Without the REBASE prototype line the prototype of Acc65I is unknown
+	// rather than Acc65I itself.
+	if !hasSchizomerData {
+		if prototype, ok := Prototype(Enzymes["Acc65I"]); ok {
+			t.Errorf("Expected the prototype of Acc65I to be unknown, got %s", prototype.Name)
+		}
+	} else if prototype, ok := Prototype(Enzymes["Acc65I"]); !ok || prototype.Name != "KpnI" {
+		t.Errorf("Expected KpnI to be the prototype of Acc65I, got %s", prototype.Name)
+	}
+}
+
+func TestCompatibleEnds(t *testing.T) {
+	compatible := map[string]enzyme.CompatibleEnd{}
+	for _, end := range CompatibleEnds(Enzymes["BamHI"]) {
+		compatible[end.Enzyme.Name] = end
+	}
+
+	for _, name := range []string{"BglII", "BclI"} {
+		end, ok := compatible[name]
+		if !ok {
+			t.Errorf("Expected %s ends to be compatible with BamHI", name)
+			continue
+		}
+		if end.OverhangType != enzyme.FivePrimeOverhang || end.OverhangSequence != "GATC" || end.Degenerate {
+			t.Errorf("Expected %s to leave an exact 5' GATC overhang, got %+v", name, end)
+		}
+	}
+	if _, ok := compatible["EcoRI"]; ok {
+		t.Errorf("Expected EcoRI ends not to be compatible with BamHI")
+	}
+
+	compatible = map[string]enzyme.CompatibleEnd{}
+	for _, end := range CompatibleEnds(Enzymes["SalI"]) {
+		compatible[end.Enzyme.Name] = end
+	}
+	if _, ok := compatible["XhoI"]; !ok {
+		t.Errorf("Expected XhoI ends to be compatible with SalI")
+	}
+}
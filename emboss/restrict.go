@@ -0,0 +1,324 @@
+/*
+Package emboss reads and writes the report of the EMBOSS restrict program,
+the format expected by pipelines built on EMBOSS. Comparing the sites found
+by this library with those EMBOSS reports for the same sequences is a simple
+way to validate either.
+*/
+package emboss
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+	"github.com/rmcl/restriction-enzymes/sequence"
+)
+
+// The sites found in one sequence of a restrict report.
+type RestrictRecord struct {
+	Name       string
+	Length     int
+	IsCircular bool
+
+	// The sites ordered by position.
+	Sites []enzyme.RecognitionSiteResult
+
+	// The lengths of the fragments of a digest with every enzyme, in the
+	// order of the fragments. Empty if the report has no fragment lengths.
+	FragmentLengths []int
+}
+
+// Build the record of the sites of the batch in the sequence and the
+// fragments of a digest with the batch, as found by Dseq.Cut.
+func NewRestrictRecord(name string, dseq *sequence.Dseq, batch *enzyme.RestrictionBatch) RestrictRecord {
+	isCircular := dseq.Geometry == constants.Circular
+	record := RestrictRecord{
+		Name:       name,
+		Length:     len(dseq.Watson),
+		IsCircular: isCircular,
+		Sites:      batch.FindAll(dseq.Watson, isCircular),
+	}
+	if len(record.Sites) > 0 {
+		record.FragmentLengths = FragmentLengths(dseq.Cut(batch))
+	}
+	return record
+}
+
+// Return the lengths of the watson strands of the fragments returned by
+// Dseq.Cut.
+func FragmentLengths(fragments []sequence.Dseq) []int {
+	lengths := make([]int, 0, len(fragments))
+	for _, fragment := range fragments {
+		lengths = append(lengths, len(fragment.Watson))
+	}
+	return lengths
+}
+
+// The columns of the table of sites.
+var restrictColumns = []string{
+	"Start", "End", "Strand", "Enzyme_name", "Restriction_site", "5prime", "3prime", "5primerev", "3primerev",
+}
+
+/*
+Write the records as an EMBOSS restrict report in its default table format.
+
+Each site is reported with the 1-based positions of the first and last base
+of its recognition site, its strand, and the cut positions of the strand the
+site is read from (5prime) and the other strand (3prime). As in EMBOSS a cut
+position is the position of the base before the cut and cuts of a circular
+sequence are wrapped around the origin, with a cut at the origin written as
+the length of the sequence. Type IIB enzymes report their second
+pair of cuts in 5primerev and 3primerev.
+*/
+func WriteRestrictReport(writer io.Writer, records []RestrictRecord) error {
+	var report strings.Builder
+	report.WriteString("########################################\n")
+	report.WriteString("# Program: restrict\n")
+	report.WriteString("# Report_format: table\n")
+	report.WriteString("########################################\n")
+
+	totalLength, totalHits := 0, 0
+	for _, record := range records {
+		totalLength += record.Length
+		totalHits += len(record.Sites)
+		writeRestrictRecord(&report, record)
+	}
+
+	report.WriteString("#---------------------------------------\n")
+	fmt.Fprintf(&report, "# Total_sequences: %d\n", len(records))
+	fmt.Fprintf(&report, "# Total_length: %d\n", totalLength)
+	fmt.Fprintf(&report, "# Reported_sequences: %d\n", len(records))
+	fmt.Fprintf(&report, "# Reported_hitcount: %d\n", totalHits)
+	report.WriteString("#---------------------------------------\n")
+
+	_, err := io.WriteString(writer, report.String())
+	return err
+}
+
+func writeRestrictRecord(report *strings.Builder, record RestrictRecord) {
+	geometry := "linear"
+	if record.IsCircular {
+		geometry = "circular"
+	}
+
+	report.WriteString("\n#=======================================\n#\n")
+	fmt.Fprintf(report, "# Sequence: %s     from: 1   to: %d\n", record.Name, record.Length)
+	fmt.Fprintf(report, "# HitCount: %d\n", len(record.Sites))
+	fmt.Fprintf(report, "#\n# DNA is %s\n#\n", geometry)
+	report.WriteString("#=======================================\n\n")
+
+	nameWidth, siteWidth := len("Enzyme_name"), len("Restriction_site")
+	for _, site := range record.Sites {
+		nameWidth = max(nameWidth, len(site.Enzyme.Name))
+		siteWidth = max(siteWidth, len(site.Enzyme.Site))
+	}
+	rowFormat := fmt.Sprintf("%%7s %%7s %%7s %%-%ds %%-%ds %%6s %%6s %%9s %%9s\n", nameWidth, siteWidth)
+
+	columns := make([]any, len(restrictColumns))
+	for i, column := range restrictColumns {
+		columns[i] = column
+	}
+	fmt.Fprintf(report, rowFormat, columns...)
+
+	frequencies := map[string]int{}
+	for _, site := range record.Sites {
+		frequencies[site.Enzyme.Name]++
+
+		strand := "+"
+		fivePrime, threePrime := site.WatsonCutIndex, site.CrickCutIndex
+		fivePrimeRev, threePrimeRev := site.WatsonCutIndex2, site.CrickCutIndex2
+		if site.Strand == constants.Crick {
+			strand = "-"
+			fivePrime, threePrime = threePrime, fivePrime
+			fivePrimeRev, threePrimeRev = threePrimeRev, fivePrimeRev
+		}
+
+		second := []string{".", "."}
+		if site.HasSecondCut {
+			second = []string{record.cutPosition(fivePrimeRev), record.cutPosition(threePrimeRev)}
+		}
+
+		fmt.Fprintf(report, rowFormat,
+			strconv.Itoa(site.RecognitionSiteIndex+1), strconv.Itoa(site.RecognitionSiteIndex+site.Enzyme.Length),
+			strand, site.Enzyme.Name, site.Enzyme.Site,
+			record.cutPosition(fivePrime), record.cutPosition(threePrime), second[0], second[1])
+	}
+
+	report.WriteString("\n#---------------------------------------\n#\n")
+	report.WriteString("# Enzymes that cut  Frequency\n")
+	names := make([]string, 0, len(frequencies))
+	for name := range frequencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(report, "# %16s  %d\n", name, frequencies[name])
+	}
+
+	if len(record.FragmentLengths) > 0 {
+		report.WriteString("#\n# Fragment lengths:\n")
+		for _, length := range record.FragmentLengths {
+			fmt.Fprintf(report, "#     %d\n", length)
+		}
+	}
+	report.WriteString("#\n#---------------------------------------\n")
+}
+
+// Return a cut position as written in the report, wrapped around the origin
+// of a circular sequence. As in EMBOSS a cut at the origin is written as the
+// length of the sequence, the position of the base before it.
+func (record *RestrictRecord) cutPosition(cut int) string {
+	cut, _ = enzyme.NormalizeCut(cut, record.Length, record.IsCircular)
+	if record.IsCircular && cut == 0 {
+		cut = record.Length
+	}
+	return strconv.Itoa(cut)
+}
+
+/*
+Parse an EMBOSS restrict report in the table format into a record for each
+sequence. The enzymes of the sites are looked up by name in the batch. An
+enzyme that is not in the batch is given the name and site of the report
+but no cut positions.
+
+The Overhang of the sites is not reported by EMBOSS and is left empty.
+*/
+func ParseRestrictReport(reader io.Reader, batch *enzyme.RestrictionBatch) ([]RestrictRecord, error) {
+	enzymes := map[string]*enzyme.Enzyme{}
+	if batch != nil {
+		for i := range batch.Enzymes {
+			enzymes[batch.Enzymes[i].Name] = &batch.Enzymes[i]
+		}
+	}
+
+	records := []RestrictRecord{}
+	var record *RestrictRecord
+	inFragments := false
+
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			comment := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+			switch {
+			case strings.HasPrefix(comment, "Sequence:"):
+				records = append(records, RestrictRecord{Sites: []enzyme.RecognitionSiteResult{}})
+				record = &records[len(records)-1]
+				inFragments = false
+				if err := parseSequenceHeader(comment, record); err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+				}
+			case record == nil:
+			case comment == "DNA is circular":
+				record.IsCircular = true
+			case comment == "Fragment lengths:":
+				inFragments = true
+			case inFragments:
+				length, err := strconv.Atoi(comment)
+				if err != nil {
+					inFragments = false
+					continue
+				}
+				record.FragmentLengths = append(record.FragmentLengths, length)
+			}
+			continue
+		}
+
+		if record == nil {
+			return nil, fmt.Errorf("line %d: site found before the sequence header", lineNumber)
+		}
+		fields := strings.Fields(line)
+		if fields[0] == restrictColumns[0] {
+			continue
+		}
+
+		site, err := parseSiteRow(fields, enzymes)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		record.Sites = append(record.Sites, site)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Parse a sequence header, e.g. "Sequence: pUC19     from: 1   to: 2686".
+func parseSequenceHeader(header string, record *RestrictRecord) error {
+	fields := strings.Fields(header)
+	if len(fields) < 2 {
+		return fmt.Errorf("invalid sequence header: %s", header)
+	}
+	record.Name = fields[1]
+
+	for i := 2; i+1 < len(fields); i++ {
+		if fields[i] == "to:" {
+			length, err := strconv.Atoi(fields[i+1])
+			if err != nil {
+				return fmt.Errorf("invalid sequence length %s", fields[i+1])
+			}
+			record.Length = length
+		}
+	}
+	return nil
+}
+
+// Parse a row of the table of sites.
+func parseSiteRow(fields []string, enzymes map[string]*enzyme.Enzyme) (enzyme.RecognitionSiteResult, error) {
+	if len(fields) < 7 {
+		return enzyme.RecognitionSiteResult{}, fmt.Errorf("expected at least 7 columns, got %d", len(fields))
+	}
+
+	positions := make([]int, len(restrictColumns))
+	hasSecondCut := len(fields) >= 9 && fields[7] != "." && fields[8] != "."
+	for _, column := range []int{0, 1, 5, 6, 7, 8} {
+		if column >= len(fields) || (column >= 7 && !hasSecondCut) {
+			continue
+		}
+		position, err := strconv.Atoi(fields[column])
+		if err != nil {
+			return enzyme.RecognitionSiteResult{}, fmt.Errorf("invalid %s %s", restrictColumns[column], fields[column])
+		}
+		positions[column] = position
+	}
+
+	strand := constants.Watson
+	fivePrime, threePrime, fivePrimeRev, threePrimeRev := positions[5], positions[6], positions[7], positions[8]
+	switch fields[2] {
+	case "+":
+	case "-":
+		strand = constants.Crick
+		fivePrime, threePrime = threePrime, fivePrime
+		fivePrimeRev, threePrimeRev = threePrimeRev, fivePrimeRev
+	default:
+		return enzyme.RecognitionSiteResult{}, fmt.Errorf("invalid strand %s", fields[2])
+	}
+
+	ez, ok := enzymes[fields[3]]
+	if !ok {
+		site := strings.ToUpper(fields[4])
+		ez = &enzyme.Enzyme{Name: fields[3], Site: site, Length: len(site)}
+		enzymes[fields[3]] = ez
+	}
+
+	return enzyme.RecognitionSiteResult{
+		Enzyme:               ez,
+		RecognitionSiteIndex: positions[0] - 1,
+		Strand:               strand,
+		WatsonCutIndex:       fivePrime,
+		CrickCutIndex:        threePrime,
+		HasSecondCut:         hasSecondCut,
+		WatsonCutIndex2:      fivePrimeRev,
+		CrickCutIndex2:       threePrimeRev,
+	}, nil
+}
@@ -0,0 +1,199 @@
+package emboss
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/db"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+	"github.com/rmcl/restriction-enzymes/sequence"
+)
+
+func testBatch(t *testing.T) enzyme.RestrictionBatch {
+	baeI, err := enzyme.ParseSite("(10/15)ACNNNNGTAYC(12/7)")
+	if err != nil {
+		t.Fatalf("Failed to parse BaeI: %s", err)
+	}
+	baeI.Name = "BaeI"
+	return enzyme.NewRestrictionBatch(db.Enzymes["EcoRI"], db.Enzymes["BamHI"], db.Enzymes["BsaI"], baeI)
+}
+
+// A sequence with EcoRI and BamHI sites, a BsaI site on the crick strand
+// and a BaeI site, which is cut on both sides.
+var testSequence = strings.Repeat("T", 20) + "ACAAAAGTACC" + strings.Repeat("T", 20) +
+	"GAATTCAAAGAGACCAAAAAAAAGGATCCAAAAAAAAAA"
+
+func TestWriteRestrictReport(t *testing.T) {
+	batch := testBatch(t)
+	dseq := sequence.NewFromWatsonStrand(testSequence, constants.Linear)
+	record := NewRestrictRecord("pTest", dseq, &batch)
+
+	var report strings.Builder
+	if err := WriteRestrictReport(&report, []RestrictRecord{record}); err != nil {
+		t.Fatalf("Failed to write the report: %s", err)
+	}
+
+	for _, line := range []string{
+		"# Sequence: pTest     from: 1   to: 90",
+		"# HitCount: 4",
+		"# DNA is linear",
+		"  Start     End  Strand Enzyme_name Restriction_site 5prime 3prime 5primerev 3primerev",
+		"     21      31       + BaeI        ACNNNNGTAYC          10      5        43        38",
+		"     52      57       + EcoRI       GAATTC               52     56         .         .",
+		"     61      66       - BsaI        GGTCTC               59     55         .         .",
+		"     75      80       + BamHI       GGATCC               75     79         .         .",
+		"#            EcoRI  1",
+		"# Fragment lengths:",
+		"# Reported_hitcount: 4",
+	} {
+		if !strings.Contains(report.String(), line+"\n") {
+			t.Errorf("Expected the line %q in the report\n%s", line, report.String())
+		}
+	}
+}
+
+func TestWriteRestrictReportCutAtOrigin(t *testing.T) {
+	batch := testBatch(t)
+	// The EcoRI site spans the origin and its watson strand cut is at it.
+	dseq := sequence.NewFromWatsonStrand("AATTC"+strings.Repeat("A", 14)+"G", constants.Circular)
+	record := NewRestrictRecord("pOrigin", dseq, &batch)
+
+	var report strings.Builder
+	if err := WriteRestrictReport(&report, []RestrictRecord{record}); err != nil {
+		t.Fatalf("Failed to write the report: %s", err)
+	}
+
+	line := "     20      25       + EcoRI       GAATTC               20      4         .         ."
+	if !strings.Contains(report.String(), line+"\n") {
+		t.Errorf("Expected the line %q in the report\n%s", line, report.String())
+	}
+}
+
+func TestParseRestrictReport(t *testing.T) {
+	batch := testBatch(t)
+	dseq := sequence.NewFromWatsonStrand(testSequence, constants.Linear)
+	record := NewRestrictRecord("pTest", dseq, &batch)
+
+	var report strings.Builder
+	WriteRestrictReport(&report, []RestrictRecord{record, {Name: "empty", Length: 10, IsCircular: true}})
+
+	records, err := ParseRestrictReport(strings.NewReader(report.String()), &batch)
+	if err != nil {
+		t.Fatalf("Failed to parse the report: %s", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	parsed := records[0]
+	if parsed.Name != "pTest" || parsed.Length != 90 || parsed.IsCircular {
+		t.Errorf("Expected the linear 90 bp pTest, got %s %d %t", parsed.Name, parsed.Length, parsed.IsCircular)
+	}
+	if !reflect.DeepEqual(parsed.FragmentLengths, record.FragmentLengths) {
+		t.Errorf("Expected fragment lengths %v, got %v", record.FragmentLengths, parsed.FragmentLengths)
+	}
+	if len(parsed.Sites) != len(record.Sites) {
+		t.Fatalf("Expected %d sites, got %d", len(record.Sites), len(parsed.Sites))
+	}
+	for i, site := range record.Sites {
+		site.Overhang, site.Overhang2 = "", ""
+		if !reflect.DeepEqual(parsed.Sites[i], site) {
+			t.Errorf("Expected site %+v, got %+v", site, parsed.Sites[i])
+		}
+	}
+
+	if empty := records[1]; empty.Name != "empty" || !empty.IsCircular || len(empty.Sites) != 0 {
+		t.Errorf("Expected an empty circular record, got %+v", empty)
+	}
+}
+
+// A report for a 60 bp circular sequence in the layout of EMBOSS restrict,
+// with the header comments this package does not write. It is written by
+// hand, not produced by EMBOSS, so it only checks that the layout is read.
+// It does not show that the sites and cut positions agree with EMBOSS.
+const embossReport = `########################################
+# Program: restrict
+# Report_format: table
+# Report_file: ptest.restrict
+########################################
+
+#=======================================
+#
+# Sequence: pTest     from: 1   to: 60
+# HitCount: 2
+#
+# Minimum cuts per enzyme: 1
+# Maximum cuts per enzyme: 2000000000
+# Minimum length of recognition site: 4
+# Blunt ends allowed
+# Sticky ends allowed
+# DNA is circular
+# Ambiguities allowed
+#
+#=======================================
+
+  Start     End  Strand Enzyme_name Restriction_site 5prime 3prime 5primerev 3primerev
+      8      13       + EcoRI       GAATTC                8     12         .         .
+     31      36       + MyEnzyme    GGATCC               31     35         .         .
+
+#---------------------------------------
+#
+# Enzymes that cut  Frequency	Isoschizomers
+#            EcoRI	1
+#         MyEnzyme	1
+#
+# Fragment lengths:
+#     37
+#     23
+#
+#---------------------------------------
+`
+
+func TestParseEmbossRestrictReport(t *testing.T) {
+	batch := testBatch(t)
+	records, err := ParseRestrictReport(strings.NewReader(embossReport), &batch)
+	if err != nil {
+		t.Fatalf("Failed to parse the report: %s", err)
+	}
+	if len(records) != 1 || !records[0].IsCircular || records[0].Length != 60 {
+		t.Fatalf("Expected one circular 60 bp record, got %+v", records)
+	}
+
+	sites := records[0].Sites
+	if len(sites) != 2 || sites[0].Enzyme.Name != "EcoRI" || sites[0].Enzyme.OverhangLength != -4 {
+		t.Errorf("Expected EcoRI from the batch first, got %+v", sites)
+	}
+	if sites[0].RecognitionSiteIndex != 7 || sites[0].WatsonCutIndex != 8 || sites[0].CrickCutIndex != 12 {
+		t.Errorf("Expected EcoRI at 7 cut at 8 and 12, got %+v", sites[0])
+	}
+	if sites[1].Enzyme.Name != "MyEnzyme" || sites[1].Enzyme.Site != "GGATCC" || sites[1].Enzyme.Length != 6 {
+		t.Errorf("Expected an enzyme for MyEnzyme with the site of the report, got %+v", sites[1].Enzyme)
+	}
+	if !reflect.DeepEqual(records[0].FragmentLengths, []int{37, 23}) {
+		t.Errorf("Expected fragment lengths [37 23], got %v", records[0].FragmentLengths)
+	}
+
+	// The sites match those found by the library in the same sequence.
+	sequence := "CCAAAAAGAATTCAAAAAAAAAAAAAAAAAGGATCCAAAAAAAAAAAAAAAAAAAAAAAA"
+	found := batch.FindAll(sequence, true)
+	if len(found) != 2 || found[0].RecognitionSiteIndex != sites[0].RecognitionSiteIndex ||
+		found[0].WatsonCutIndex != sites[0].WatsonCutIndex || found[1].WatsonCutIndex != sites[1].WatsonCutIndex {
+		t.Errorf("Expected the reported sites to match %+v", found)
+	}
+}
+
+func TestFragmentLengths(t *testing.T) {
+	batch := testBatch(t)
+	watson := "AAAAAGAATTCAAAAAAAAAAGGATCCAAAA"
+
+	circular := sequence.NewFromWatsonStrand(watson, constants.Circular)
+	if lengths := FragmentLengths(circular.Cut(&batch)); !reflect.DeepEqual(lengths, []int{15, 16}) {
+		t.Errorf("Expected the fragment across the origin first, got %v", lengths)
+	}
+	linear := sequence.NewFromWatsonStrand(watson, constants.Linear)
+	if lengths := FragmentLengths(linear.Cut(&batch)); !reflect.DeepEqual(lengths, []int{6, 16, 9}) {
+		t.Errorf("Expected the three linear fragments, got %v", lengths)
+	}
+}
@@ -0,0 +1,241 @@
+package enzyme
+
+import (
+	"sort"
+)
+
+// A restriction analysis of a sequence with a batch of enzymes, similar to
+// Biopython's Restriction.Analysis. The sequence is searched once when the
+// analysis is created and the methods answer questions about the result.
+type Analysis struct {
+	Batch      RestrictionBatch
+	Sequence   string
+	IsCircular bool
+
+	// The sorted, distinct watson strand cut positions of each enzyme in the
+	// batch. Cuts beyond the ends of a linear sequence are dropped and cuts
+	// in a circular sequence are wrapped around the origin.
+	CutsByEnzyme map[string][]int
+}
+
+// A fragment of the sequence between two cuts on the watson strand. The
+// fragment of a circular sequence that spans the origin has an End smaller
+// than its Start.
+type Fragment struct {
+	Start    int
+	End      int
+	Length   int
+	Sequence string
+}
+
+// Search the sequence with every enzyme in the batch and return the
+// analysis of the result.
+func NewAnalysis(batch RestrictionBatch, sequence string, isCircular bool) *Analysis {
+	analysis := &Analysis{
+		Batch:        batch,
+		Sequence:     sequence,
+		IsCircular:   isCircular,
+		CutsByEnzyme: make(map[string][]int, len(batch.Enzymes)),
+	}
+
+	for _, enzyme := range batch.Enzymes {
+		analysis.CutsByEnzyme[enzyme.Name] = []int{}
+	}
+
+	for _, result := range batch.FindAll(sequence, isCircular) {
+		analysis.CutsByEnzyme[result.Enzyme.Name] = append(
+			analysis.CutsByEnzyme[result.Enzyme.Name],
+			result.WatsonCutIndexes()...)
+	}
+
+	for name, cuts := range analysis.CutsByEnzyme {
+		analysis.CutsByEnzyme[name] = NormalizeCuts(cuts, len(sequence), isCircular)
+	}
+
+	return analysis
+}
+
+// Return the cut as a position within a sequence of the given length. A cut
+// in a circular sequence is wrapped around the origin. A cut in a linear
+// sequence is returned unchanged, and the second return value is false if
+// it does not divide the sequence.
+func NormalizeCut(cut int, length int, isCircular bool) (int, bool) {
+	if length == 0 {
+		return cut, false
+	}
+	if isCircular {
+		return ((cut % length) + length) % length, true
+	}
+	return cut, cut > 0 && cut < length
+}
+
+// Return the distinct cuts as sorted positions within a sequence of the
+// given length. Cuts that do not divide a linear sequence are dropped, see
+// NormalizeCut.
+func NormalizeCuts(cuts []int, length int, isCircular bool) []int {
+	normalized := make([]int, 0, len(cuts))
+	for _, cut := range cuts {
+		if cut, ok := NormalizeCut(cut, length, isCircular); ok {
+			normalized = append(normalized, cut)
+		}
+	}
+	sort.Ints(normalized)
+	return dedupeSortedInts(normalized)
+}
+
+func dedupeSortedInts(values []int) []int {
+	deduped := values[:0]
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			deduped = append(deduped, value)
+		}
+	}
+	return deduped
+}
+
+// Return the watson strand cut positions of the enzyme in ascending order.
+func (analysis *Analysis) Cuts(name string) []int {
+	return analysis.CutsByEnzyme[name]
+}
+
+// Return the names of the enzymes in the batch, in order, whose cuts match.
+func (analysis *Analysis) enzymesWhere(match func(cuts []int) bool) []string {
+	names := []string{}
+	for _, enzyme := range analysis.Batch.Enzymes {
+		if match(analysis.CutsByEnzyme[enzyme.Name]) {
+			names = append(names, enzyme.Name)
+		}
+	}
+	return names
+}
+
+// Return the enzymes that cut the sequence at least once.
+func (analysis *Analysis) Cutters() []string {
+	return analysis.enzymesWhere(func(cuts []int) bool {
+		return len(cuts) > 0
+	})
+}
+
+// Return the enzymes that do not cut the sequence.
+func (analysis *Analysis) NonCutters() []string {
+	return analysis.NCutters(0)
+}
+
+// Return the enzymes that cut the sequence exactly once.
+func (analysis *Analysis) OnceCutters() []string {
+	return analysis.NCutters(1)
+}
+
+// Return the enzymes that cut the sequence exactly twice.
+func (analysis *Analysis) TwiceCutters() []string {
+	return analysis.NCutters(2)
+}
+
+// Return the enzymes that cut the sequence exactly n times.
+func (analysis *Analysis) NCutters(n int) []string {
+	return analysis.enzymesWhere(func(cuts []int) bool {
+		return len(cuts) == n
+	})
+}
+
+// Return the enzymes with at least one cut between start (inclusive) and
+// end (exclusive).
+func (analysis *Analysis) Between(start int, end int) []string {
+	return analysis.enzymesWhere(func(cuts []int) bool {
+		return countBetween(cuts, start, end) > 0
+	})
+}
+
+// Return the enzymes that cut between start (inclusive) and end (exclusive)
+// and nowhere else.
+func (analysis *Analysis) OnlyBetween(start int, end int) []string {
+	return analysis.enzymesWhere(func(cuts []int) bool {
+		return len(cuts) > 0 && countBetween(cuts, start, end) == len(cuts)
+	})
+}
+
+// Return the enzymes with at least one cut outside of start (inclusive) to
+// end (exclusive).
+func (analysis *Analysis) Outside(start int, end int) []string {
+	return analysis.enzymesWhere(func(cuts []int) bool {
+		return countBetween(cuts, start, end) < len(cuts)
+	})
+}
+
+// Return the enzymes that cut the sequence but never between start
+// (inclusive) and end (exclusive), e.g. enzymes that leave an insert intact.
+func (analysis *Analysis) OnlyOutside(start int, end int) []string {
+	return analysis.enzymesWhere(func(cuts []int) bool {
+		return len(cuts) > 0 && countBetween(cuts, start, end) == 0
+	})
+}
+
+func countBetween(cuts []int, start int, end int) int {
+	count := 0
+	for _, cut := range cuts {
+		if cut >= start && cut < end {
+			count++
+		}
+	}
+	return count
+}
+
+// Return the fragments produced by digesting the sequence with the enzyme,
+// sorted by position. An enzyme that does not cut leaves the whole sequence
+// as a single fragment.
+func (analysis *Analysis) Fragments(name string) []Fragment {
+	return fragmentsFromCuts(analysis.Sequence, analysis.CutsByEnzyme[name], analysis.IsCircular)
+}
+
+// Return the fragments produced by digesting the sequence with each enzyme
+// in the batch.
+func (analysis *Analysis) FragmentsByEnzyme() map[string][]Fragment {
+	fragments := make(map[string][]Fragment, len(analysis.Batch.Enzymes))
+	for _, enzyme := range analysis.Batch.Enzymes {
+		fragments[enzyme.Name] = analysis.Fragments(enzyme.Name)
+	}
+	return fragments
+}
+
+// Return the fragments of the sequence between the sorted watson strand cut
+// positions.
+func fragmentsFromCuts(sequence string, cuts []int, isCircular bool) []Fragment {
+	if len(cuts) == 0 {
+		return []Fragment{{Start: 0, End: len(sequence), Length: len(sequence), Sequence: sequence}}
+	}
+
+	fragments := []Fragment{}
+	if !isCircular {
+		starts := append([]int{0}, cuts...)
+		ends := append(append([]int{}, cuts...), len(sequence))
+		for i := range starts {
+			fragments = append(fragments, Fragment{
+				Start:    starts[i],
+				End:      ends[i],
+				Length:   ends[i] - starts[i],
+				Sequence: sequence[starts[i]:ends[i]],
+			})
+		}
+		return fragments
+	}
+
+	for i := 0; i < len(cuts)-1; i++ {
+		fragments = append(fragments, Fragment{
+			Start:    cuts[i],
+			End:      cuts[i+1],
+			Length:   cuts[i+1] - cuts[i],
+			Sequence: sequence[cuts[i]:cuts[i+1]],
+		})
+	}
+
+	// The last fragment runs from the last cut around the origin to the
+	// first cut.
+	last, first := cuts[len(cuts)-1], cuts[0]
+	fragments = append(fragments, Fragment{
+		Start:    last,
+		End:      first,
+		Length:   len(sequence) - last + first,
+		Sequence: sequence[last:] + sequence[:first],
+	})
+	return fragments
+}
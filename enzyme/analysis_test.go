@@ -0,0 +1,93 @@
+package enzyme
+
+import (
+	"reflect"
+	"testing"
+)
+
+func analysisFixture(isCircular bool) *Analysis {
+	batch := NewRestrictionBatch(
+		FIXTURES["EcoRI"],
+		FIXTURES["BamHI"],
+		FIXTURES["BsaI"],
+		FIXTURES["XbaI"],
+	)
+	// EcoRI cuts at 3 and 23, BamHI at 13 and XbaI at 33.
+	sequence := "AAGAATTCAAAAGGATCCAAAAGAATTCAAATCTAGAAAA"
+	return NewAnalysis(batch, sequence, isCircular)
+}
+
+func TestAnalysisCutters(t *testing.T) {
+	analysis := analysisFixture(false)
+
+	if !reflect.DeepEqual(analysis.Cuts("EcoRI"), []int{3, 23}) {
+		t.Errorf("Expected EcoRI cuts [3 23], got %v", analysis.Cuts("EcoRI"))
+	}
+	if !reflect.DeepEqual(analysis.Cutters(), []string{"EcoRI", "BamHI", "XbaI"}) {
+		t.Errorf("Expected cutters [EcoRI BamHI XbaI], got %v", analysis.Cutters())
+	}
+	if !reflect.DeepEqual(analysis.NonCutters(), []string{"BsaI"}) {
+		t.Errorf("Expected non cutters [BsaI], got %v", analysis.NonCutters())
+	}
+	if !reflect.DeepEqual(analysis.OnceCutters(), []string{"BamHI", "XbaI"}) {
+		t.Errorf("Expected once cutters [BamHI XbaI], got %v", analysis.OnceCutters())
+	}
+	if !reflect.DeepEqual(analysis.TwiceCutters(), []string{"EcoRI"}) {
+		t.Errorf("Expected twice cutters [EcoRI], got %v", analysis.TwiceCutters())
+	}
+}
+
+func TestAnalysisRanges(t *testing.T) {
+	analysis := analysisFixture(false)
+
+	if !reflect.DeepEqual(analysis.Between(0, 20), []string{"EcoRI", "BamHI"}) {
+		t.Errorf("Expected [EcoRI BamHI] between 0 and 20, got %v", analysis.Between(0, 20))
+	}
+	if !reflect.DeepEqual(analysis.OnlyBetween(0, 20), []string{"BamHI"}) {
+		t.Errorf("Expected [BamHI] only between 0 and 20, got %v", analysis.OnlyBetween(0, 20))
+	}
+	if !reflect.DeepEqual(analysis.Outside(0, 20), []string{"EcoRI", "XbaI"}) {
+		t.Errorf("Expected [EcoRI XbaI] outside 0 to 20, got %v", analysis.Outside(0, 20))
+	}
+	if !reflect.DeepEqual(analysis.OnlyOutside(0, 20), []string{"XbaI"}) {
+		t.Errorf("Expected [XbaI] only outside 0 to 20, got %v", analysis.OnlyOutside(0, 20))
+	}
+}
+
+func TestAnalysisFragments(t *testing.T) {
+	linear := analysisFixture(false)
+	lengths := []int{}
+	for _, fragment := range linear.Fragments("EcoRI") {
+		lengths = append(lengths, fragment.Length)
+	}
+	if !reflect.DeepEqual(lengths, []int{3, 20, 17}) {
+		t.Errorf("Expected linear EcoRI fragments of [3 20 17], got %v", lengths)
+	}
+
+	circular := analysisFixture(true)
+	fragments := circular.Fragments("EcoRI")
+	if len(fragments) != 2 || fragments[1].Start != 23 || fragments[1].End != 3 || fragments[1].Length != 20 {
+		t.Errorf("Expected a circular EcoRI fragment from 23 to 3, got %v", fragments)
+	}
+	if fragments[1].Sequence != "AATTCAAATCTAGAAAAAAG" {
+		t.Errorf("Expected the fragment to span the origin, got %s", fragments[1].Sequence)
+	}
+
+	if len(circular.FragmentsByEnzyme()["BsaI"]) != 1 {
+		t.Errorf("Expected a non cutter to leave a single fragment")
+	}
+}
+
+func TestNormalizeCuts(t *testing.T) {
+	cuts := []int{12, -2, 3, 0, 10, 3}
+
+	if normalized := NormalizeCuts(cuts, 10, false); !reflect.DeepEqual(normalized, []int{3}) {
+		t.Errorf("Expected the cuts dividing the linear sequence [3], got %v", normalized)
+	}
+	if normalized := NormalizeCuts(cuts, 10, true); !reflect.DeepEqual(normalized, []int{0, 2, 3, 8}) {
+		t.Errorf("Expected the cuts wrapped around the origin [0 2 3 8], got %v", normalized)
+	}
+	if cut, ok := NormalizeCut(12, 10, false); cut != 12 || ok {
+		t.Errorf("Expected a cut past the end of a linear sequence to be returned unchanged, got %d %v", cut, ok)
+	}
+}
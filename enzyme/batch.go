@@ -1,41 +1,53 @@
 package enzyme
 
 import (
-	"regexp"
-	"strings"
+	"context"
+	"sort"
+	"sync"
 
 	"github.com/rmcl/restriction-enzymes/constants"
 )
 
 type RestrictionBatch struct {
-	Enzymes         []Enzyme
-	combinedRegex   *regexp.Regexp
-	fwdSiteToEnzyme map[string][]*Enzyme
-	revSiteToEnzyme map[string][]*Enzyme
+	Enzymes []Enzyme
+	matcher *matcherCache
 }
 
-// Create a new restriction batch with the given enzymes.
+// The site matcher of a batch. It is built by the first search after the
+// enzymes of the batch change, so adding enzymes one at a time does not
+// rebuild it for each one.
+type matcherCache struct {
+	mutex   sync.Mutex
+	matcher *siteMatcher
+}
+
+// Create a new restriction batch with the given enzymes. Enzymes are unique
+// by name; only the first enzyme with a name is kept.
+//
+// A batch may be searched from many goroutines at once. It must not be
+// searched while enzymes are being added or removed.
 func NewRestrictionBatch(enzymes ...Enzyme) RestrictionBatch {
-	return RestrictionBatch{
-		Enzymes: enzymes,
-	}
+	restrictionBatch := RestrictionBatch{}
+	restrictionBatch.setEnzymes(appendUniqueEnzymes(nil, enzymes...))
+	return restrictionBatch
 }
 
-// Add one or more enzymes to the restriction batch.
+// Add one or more enzymes to the restriction batch. Enzymes already in the
+// batch are skipped.
 func (restrictionBatch *RestrictionBatch) Add(enzyme ...Enzyme) {
-	restrictionBatch.Enzymes = append(restrictionBatch.Enzymes, enzyme...)
-	restrictionBatch.combinedRegex = nil
+	restrictionBatch.setEnzymes(appendUniqueEnzymes(restrictionBatch.Enzymes, enzyme...))
 }
 
-// Add all the enzymes from another restriction batch to this one.
+// Add all the enzymes from another restriction batch to this one. Enzymes
+// already in the batch are skipped.
 func (restrictionBatch *RestrictionBatch) AddBatch(batch RestrictionBatch) {
-	restrictionBatch.Enzymes = append(restrictionBatch.Enzymes, batch.Enzymes...)
-	restrictionBatch.combinedRegex = nil
+	restrictionBatch.Add(batch.Enzymes...)
 }
 
 // Return a mapping of enzyme name to a list of sites in the sequence it cuts.
 // This returns the position of the cut site on the watson strand to mirror
-// Biopython's interface.
+// Biopython's interface. Type IIB enzymes report both cut sites for each
+// recognition site.
 //
 // If isCircular is true, the sequence is treated as circular and the
 // function will return a recognition site, even if it spans the
@@ -43,33 +55,59 @@ func (restrictionBatch *RestrictionBatch) AddBatch(batch RestrictionBatch) {
 func (enzymeBatch *RestrictionBatch) Search(sequence string, isCircular bool) (map[string][]int, error) {
 	watsonCutSitesByEnzyme := make(map[string][]int)
 
-	lastSitePosition := 0
-	for lastSitePosition >= 0 {
+	for _, result := range enzymeBatch.FindAll(sequence, isCircular) {
+		watsonCutSitesByEnzyme[result.Enzyme.Name] = append(
+			watsonCutSitesByEnzyme[result.Enzyme.Name],
+			result.WatsonCutIndexes()...)
+	}
 
-		results := enzymeBatch.GetNextRecognitionSite(
-			sequence,
-			lastSitePosition,
-			isCircular,
-		)
+	return watsonCutSitesByEnzyme, nil
+}
 
-		if results == nil {
-			break
-		}
+// Return every recognition site of every enzyme in the batch ordered by
+// position. The sequence is read once regardless of the number of enzymes.
+//
+// If isCircular is true, the sequence is treated as circular and sites
+// spanning the beginning and end of the sequence are included.
+func (restrictionBatch *RestrictionBatch) FindAll(sequence string, isCircular bool) []RecognitionSiteResult {
+	results, _ := restrictionBatch.findAll(context.Background(), sequence, isCircular)
+	return results
+}
 
-		for _, result := range results {
-			enzyme := result.Enzyme
-			if enzymeCutSites, ok := watsonCutSitesByEnzyme[result.Enzyme.Name]; ok {
-				enzymeCutSites = append(enzymeCutSites, result.WatsonCutIndex)
-				watsonCutSitesByEnzyme[result.Enzyme.Name] = enzymeCutSites
-			} else {
-				watsonCutSitesByEnzyme[enzyme.Name] = []int{result.WatsonCutIndex}
-			}
+// FindAll that stops reading the sequence and returns the context's error
+// once the context ends.
+func (restrictionBatch *RestrictionBatch) findAll(
+	ctx context.Context,
+	sequence string,
+	isCircular bool,
+) ([]RecognitionSiteResult, error) {
+	matcher := restrictionBatch.getMatcher()
+
+	matches := []patternMatch{}
+	matcher.scan(sequence, 0, isCircular, ctx.Done(), func(match patternMatch, end int) bool {
+		matches = append(matches, match)
+		return true
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-			lastSitePosition = result.RecognitionSiteIndex + 1
+	// Matches are found in the order they end. Longer sites end later so
+	// restore the order of the sites by their start.
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+
+	results := make([]RecognitionSiteResult, 0, len(matches))
+	for len(matches) > 0 {
+		siteEnd := 1
+		for siteEnd < len(matches) && matches[siteEnd].start == matches[0].start {
+			siteEnd++
 		}
+		results = restrictionBatch.appendResultsForMatches(matcher, results, sequence, matches[:siteEnd], isCircular)
+		matches = matches[siteEnd:]
 	}
-
-	return watsonCutSitesByEnzyme, nil
+	return results, nil
 }
 
 // Get the next recognition site in the sequence after the offset
@@ -92,160 +130,110 @@ func (restrictionBatch *RestrictionBatch) GetNextRecognitionSite(
 		return nil
 	}
 
-	if restrictionBatch.combinedRegex == nil {
-		restrictionBatch.buildCombinedPattern()
-	}
-
-	sequence = strings.ToLower(sequence)
-	searchSequence := sequence[offset:]
-	originalSequenceLength := len(sequence)
-
-	if isCircular {
-
-		arbitrary_extension_length := 10
+	matcher := restrictionBatch.getMatcher()
 
-		if len(sequence) > arbitrary_extension_length {
-			searchSequence += sequence[:arbitrary_extension_length]
-		} else {
-			// If the sequence is shorter then the recognition site, we
-			// need to append the sequence to itself to avoid an index
-			// out of range error.
-			searchSequence += sequence
+	// A longer site that ends after a shorter one may still start before
+	// it, so keep scanning until no site can start before the first one
+	// found.
+	matches := []patternMatch{}
+	matcher.scan(sequence, offset, isCircular, nil, func(match patternMatch, end int) bool {
+		if len(matches) > 0 && end >= matches[0].start+matcher.maxLength {
+			return false
 		}
-	}
-
-	// lower case the search sequence
-	searchSequence = strings.ToLower(searchSequence)
-
-	match := restrictionBatch.combinedRegex.FindStringIndex(searchSequence)
-	if match == nil {
-		return nil
-	}
+		if len(matches) > 0 && match.start > matches[0].start {
+			return true
+		}
+		if len(matches) > 0 && match.start < matches[0].start {
+			matches = matches[:0]
+		}
+		matches = append(matches, match)
+		return true
+	})
 
-	// If the match is beyond the original sequence length, we
-	// should ignore it
-	if match[0]+offset >= originalSequenceLength {
+	if len(matches) == 0 {
 		return nil
 	}
 
-	matchedSite := searchSequence[match[0]:match[1]]
-
-	return restrictionBatch.getResultsForMatchSite(
-		matchedSite,
-		match[0]+offset,
-		sequence)
+	return restrictionBatch.appendResultsForMatches(matcher, nil, sequence, matches, isCircular)
 }
 
-func (restrictionBatch *RestrictionBatch) getResultsForMatchSite(
-	matchedSite string,
-	matchStart int,
+// Append a result for every enzyme recognizing one of the matched patterns.
+// All matches start at the same position. Results are ordered by site
+// length, then watson before crick, then by the order of the enzymes in the
+// batch.
+func (restrictionBatch *RestrictionBatch) appendResultsForMatches(
+	matcher *siteMatcher,
+	results []RecognitionSiteResult,
 	sequence string,
+	matches []patternMatch,
+	isCircular bool,
 ) []RecognitionSiteResult {
 
-	results := []RecognitionSiteResult{}
-	matchedEnzymeNames := map[string]bool{}
-
-	// Match the recognition site to the enzymes on the forward strand
-	strand := constants.Watson
-	fwdEnzymes := restrictionBatch.fwdSiteToEnzyme[matchedSite]
-	for _, fwdEnzyme := range fwdEnzymes {
-		watsonCutIndex, crickCutIndex := fwdEnzyme.GetCutSitePositions(matchStart, strand)
-		results = append(results, RecognitionSiteResult{
-			RecognitionSiteIndex: matchStart,
-			Enzyme:               fwdEnzyme,
-			Strand:               strand,
-
-			WatsonCutIndex: watsonCutIndex,
-			CrickCutIndex:  crickCutIndex,
-		})
-
-		matchedEnzymeNames[fwdEnzyme.Name] = true
-	}
-
-	strand = constants.Crick
-	revEnzymes := restrictionBatch.revSiteToEnzyme[matchedSite]
-	for _, revEnzyme := range revEnzymes {
-
-		// If the enzyme has already been matched on the forward strand,
-		// we should skip it. This can happen if the recognition site is
-		// a palindrome.
-		if _, ok := matchedEnzymeNames[revEnzyme.Name]; ok {
-			continue
+	hits := []lengthHit{}
+	for _, match := range matches {
+		pattern := matcher.patterns[match.pattern]
+		for _, hit := range pattern.hits {
+			hits = append(hits, lengthHit{hit, pattern.length})
 		}
+	}
 
-		watsonCutIndex, crickCutIndex := revEnzyme.GetCutSitePositions(matchStart, strand)
-		results = append(results, RecognitionSiteResult{
-			RecognitionSiteIndex: matchStart,
-			Enzyme:               revEnzyme,
-			Strand:               strand,
-
-			WatsonCutIndex: watsonCutIndex,
-			CrickCutIndex:  crickCutIndex,
+	if len(hits) > 1 {
+		sort.SliceStable(hits, func(i, j int) bool {
+			if hits[i].length != hits[j].length {
+				return hits[i].length < hits[j].length
+			}
+			if hits[i].strand != hits[j].strand {
+				return hits[i].strand == constants.Watson
+			}
+			return hits[i].enzymeIndex < hits[j].enzymeIndex
 		})
 	}
 
-	// Confirm that there are not multiple enzymes that match the same site
-	// This is possible if the recognition site is 1 bp shorter than the additional
-	// enzymes recognition site
-	for i := 1; i < 5; i++ {
-		endIndex := matchStart + i
-		if endIndex >= len(sequence) {
-			break
-		}
-
-		additionalSite := sequence[matchStart:endIndex]
-		additionalEnzymes := restrictionBatch.fwdSiteToEnzyme[additionalSite]
-		for _, addEnzyme := range additionalEnzymes {
-			watsonCutIndex, crickCutIndex := addEnzyme.GetCutSitePositions(matchStart, strand)
-			results = append(results, RecognitionSiteResult{
-				RecognitionSiteIndex: matchStart,
-				Enzyme:               addEnzyme,
-				Strand:               strand,
-
-				WatsonCutIndex: watsonCutIndex,
-				CrickCutIndex:  crickCutIndex,
-			})
-		}
-
+	// A degenerate site that is not a palindrome may match both strands at
+	// the same position. Both are reported, as they are by the enzyme's own
+	// search, since the two strands give different cuts.
+	for _, hit := range hits {
+		enzyme := &restrictionBatch.Enzymes[hit.enzymeIndex]
+		results = append(results, newRecognitionSiteResult(enzyme, sequence, matches[0].start, hit.strand, isCircular))
 	}
 
 	return results
 }
 
-// Build a combined regular expression pattern for all the enzymes in the batch.
-// this private method is called when the combinedRegex is nil and is used to
-// build the combined pattern for all the enzymes in the batch. This is much
-// faster than searching for each enzyme individually.
-func (restrictionBatch *RestrictionBatch) buildCombinedPattern() {
-	restrictionBatch.fwdSiteToEnzyme = make(map[string][]*Enzyme)
-	restrictionBatch.revSiteToEnzyme = make(map[string][]*Enzyme)
-
-	if len(restrictionBatch.Enzymes) == 0 {
-		restrictionBatch.combinedRegex = nil
-		return
-	}
-
-	patternString := "("
-	for _, enzyme := range restrictionBatch.Enzymes {
-
-		fwdExpStr := strings.ToLower(enzyme.RegexpFor.String())
-		revExpStr := strings.ToLower(enzyme.RegexpRev.String())
-
-		patternString += "" + fwdExpStr + "|" + revExpStr + "|"
-
-		// Unfortunately, we need to strip the first 4 characters from the
-		// the regular expression pattern because it is "(?i)"
-		fwdSite := strings.ToLower(enzyme.RegexpFor.String()[4:])
-		revSite := strings.ToLower(enzyme.RegexpRev.String()[4:])
+// A pattern hit and the length of the pattern's site.
+type lengthHit struct {
+	patternHit
+	length int
+}
 
-		if restrictionBatch.fwdSiteToEnzyme[fwdSite] == nil {
-			restrictionBatch.fwdSiteToEnzyme[fwdSite] = []*Enzyme{}
-			restrictionBatch.revSiteToEnzyme[revSite] = []*Enzyme{}
-		}
+// Guards the creation of the matcher cache of a batch built without
+// NewRestrictionBatch, which may be searched from many goroutines at once.
+var matcherCacheMutex sync.Mutex
+
+// Return the matcher cache of the batch, creating it for a batch built
+// without NewRestrictionBatch.
+func (restrictionBatch *RestrictionBatch) getMatcherCache() *matcherCache {
+	matcherCacheMutex.Lock()
+	defer matcherCacheMutex.Unlock()
+	if restrictionBatch.matcher == nil {
+		restrictionBatch.matcher = &matcherCache{}
+	}
+	return restrictionBatch.matcher
+}
 
-		restrictionBatch.fwdSiteToEnzyme[fwdSite] = append(restrictionBatch.fwdSiteToEnzyme[fwdSite], &enzyme)
-		restrictionBatch.revSiteToEnzyme[revSite] = append(restrictionBatch.revSiteToEnzyme[revSite], &enzyme)
+// Return the site matcher for the enzymes in the batch. Searching every
+// enzyme with one matcher is much faster than searching for each enzyme
+// individually.
+//
+// The stored matcher is only used if it was built for the names and sites
+// of the enzymes now in the batch, otherwise it is rebuilt.
+func (restrictionBatch *RestrictionBatch) getMatcher() *siteMatcher {
+	cache := restrictionBatch.getMatcherCache()
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if cache.matcher == nil || !cache.matcher.isFor(restrictionBatch.Enzymes) {
+		cache.matcher = newSiteMatcher(restrictionBatch.Enzymes)
 	}
-	patternString = patternString[:len(patternString)-1] + ")"
-	restrictionBatch.combinedRegex = regexp.MustCompile(patternString)
+	return cache.matcher
 }
@@ -0,0 +1,115 @@
+package enzyme
+
+// Replace the enzymes in the batch and mark its site matcher to be rebuilt
+// by the next search. Every change to the enzymes of a batch goes through
+// here. The batch gets a new cache rather than clearing the old one, which
+// copies of the batch may still share.
+func (restrictionBatch *RestrictionBatch) setEnzymes(enzymes []Enzyme) {
+	restrictionBatch.Enzymes = enzymes
+	restrictionBatch.matcher = &matcherCache{}
+}
+
+// Return a copy of the existing enzymes with every new enzyme whose name is
+// not already present appended, in order.
+func appendUniqueEnzymes(existing []Enzyme, enzymes ...Enzyme) []Enzyme {
+	names := make(map[string]bool, len(existing)+len(enzymes))
+	unique := make([]Enzyme, 0, len(existing)+len(enzymes))
+
+	for _, group := range [][]Enzyme{existing, enzymes} {
+		for _, enzyme := range group {
+			if names[enzyme.Name] {
+				continue
+			}
+			names[enzyme.Name] = true
+			unique = append(unique, enzyme)
+		}
+	}
+	return unique
+}
+
+// Return true if the batch contains an enzyme with the name.
+func (restrictionBatch *RestrictionBatch) Contains(name string) bool {
+	for _, enzyme := range restrictionBatch.Enzymes {
+		if enzyme.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the names of the enzymes in the batch, in order.
+func (restrictionBatch *RestrictionBatch) Names() []string {
+	names := make([]string, len(restrictionBatch.Enzymes))
+	for i, enzyme := range restrictionBatch.Enzymes {
+		names[i] = enzyme.Name
+	}
+	return names
+}
+
+// Remove the enzymes with the names from the batch. Names not in the batch
+// are ignored.
+func (restrictionBatch *RestrictionBatch) Remove(names ...string) {
+	removed := make(map[string]bool, len(names))
+	for _, name := range names {
+		removed[name] = true
+	}
+
+	restrictionBatch.setEnzymes(restrictionBatch.filterEnzymes(func(enzyme Enzyme) bool {
+		return !removed[enzyme.Name]
+	}))
+}
+
+// Remove enzymes with the same name as an earlier enzyme in the batch. This
+// is only needed if Enzymes was changed directly; batches created with
+// NewRestrictionBatch never hold duplicates.
+func (restrictionBatch *RestrictionBatch) Deduplicate() {
+	restrictionBatch.setEnzymes(appendUniqueEnzymes(nil, restrictionBatch.Enzymes...))
+}
+
+// Return a new batch of the enzymes for which keep returns true, in order.
+func (restrictionBatch *RestrictionBatch) Filter(keep func(Enzyme) bool) RestrictionBatch {
+	return NewRestrictionBatch(restrictionBatch.filterEnzymes(keep)...)
+}
+
+// Return a new batch of the enzymes in either batch. The enzymes of this
+// batch come first followed by those only in the other batch.
+func (restrictionBatch *RestrictionBatch) Union(other RestrictionBatch) RestrictionBatch {
+	return NewRestrictionBatch(appendUniqueEnzymes(restrictionBatch.Enzymes, other.Enzymes...)...)
+}
+
+// Return a new batch of the enzymes in both batches, in the order of this
+// batch.
+func (restrictionBatch *RestrictionBatch) Intersection(other RestrictionBatch) RestrictionBatch {
+	otherNames := other.nameSet()
+	return restrictionBatch.Filter(func(enzyme Enzyme) bool {
+		return otherNames[enzyme.Name]
+	})
+}
+
+// Return a new batch of the enzymes in this batch that are not in the other
+// batch, in the order of this batch. For example the enzymes that cut an
+// insert but not the vector backbone.
+func (restrictionBatch *RestrictionBatch) Difference(other RestrictionBatch) RestrictionBatch {
+	otherNames := other.nameSet()
+	return restrictionBatch.Filter(func(enzyme Enzyme) bool {
+		return !otherNames[enzyme.Name]
+	})
+}
+
+func (restrictionBatch *RestrictionBatch) nameSet() map[string]bool {
+	names := make(map[string]bool, len(restrictionBatch.Enzymes))
+	for _, enzyme := range restrictionBatch.Enzymes {
+		names[enzyme.Name] = true
+	}
+	return names
+}
+
+func (restrictionBatch *RestrictionBatch) filterEnzymes(keep func(Enzyme) bool) []Enzyme {
+	kept := []Enzyme{}
+	for _, enzyme := range restrictionBatch.Enzymes {
+		if keep(enzyme) {
+			kept = append(kept, enzyme)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,117 @@
+package enzyme
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatchDeduplicatesByName(t *testing.T) {
+	batch := NewRestrictionBatch(FIXTURES["EcoRI"], FIXTURES["BsaI"], FIXTURES["EcoRI"])
+	batch.Add(FIXTURES["BsaI"], FIXTURES["BamHI"])
+	batch.AddBatch(NewRestrictionBatch(FIXTURES["EcoRI"], FIXTURES["XbaI"]))
+
+	if !reflect.DeepEqual(batch.Names(), []string{"EcoRI", "BsaI", "BamHI", "XbaI"}) {
+		t.Errorf("Expected [EcoRI BsaI BamHI XbaI], got %v", batch.Names())
+	}
+
+	batch.Enzymes = append(batch.Enzymes, FIXTURES["BsaI"])
+	batch.Deduplicate()
+	if len(batch.Enzymes) != 4 {
+		t.Errorf("Expected 4 enzymes after deduplicating, got %d", len(batch.Enzymes))
+	}
+}
+
+func TestBatchSearchAfterEnzymeReplacedInPlace(t *testing.T) {
+	batch := NewRestrictionBatch(FIXTURES["EcoRI"])
+	batch.Enzymes[0] = FIXTURES["BamHI"]
+
+	cuts, err := batch.Search("AAGAATTCAAGGATCCAA", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cuts, map[string][]int{"BamHI": {11}}) {
+		t.Errorf("Expected BamHI to cut at 11, got %v", cuts)
+	}
+}
+
+func TestBatchBuildsMatcherOnSearch(t *testing.T) {
+	batch := NewRestrictionBatch()
+	for _, name := range []string{"EcoRI", "BsaI", "BamHI"} {
+		batch.Add(FIXTURES[name])
+	}
+	if batch.matcher.matcher != nil {
+		t.Fatal("Expected no matcher before searching")
+	}
+
+	batch.FindAll("GAATTC", false)
+	matcher := batch.matcher.matcher
+	batch.FindAll("GGATCC", false)
+	if matcher == nil || batch.matcher.matcher != matcher {
+		t.Error("Expected the matcher to be built once and reused")
+	}
+
+	batch.Add(FIXTURES["XbaI"])
+	if batch.matcher.matcher != nil {
+		t.Error("Expected adding an enzyme to clear the matcher")
+	}
+}
+
+func TestBatchLiteralReusesMatcher(t *testing.T) {
+	batch := RestrictionBatch{Enzymes: []Enzyme{FIXTURES["EcoRI"], FIXTURES["BamHI"]}}
+
+	batch.GetNextRecognitionSite("GAATTCGGATCC", 0, false)
+	matcher := batch.matcher.matcher
+	batch.GetNextRecognitionSite("GAATTCGGATCC", 1, false)
+	if matcher == nil || batch.matcher.matcher != matcher {
+		t.Error("Expected a batch built without NewRestrictionBatch to build its matcher once")
+	}
+}
+
+func TestBatchSetOperations(t *testing.T) {
+	insert := NewRestrictionBatch(FIXTURES["EcoRI"], FIXTURES["BsaI"], FIXTURES["BamHI"])
+	backbone := NewRestrictionBatch(FIXTURES["XbaI"], FIXTURES["BamHI"])
+
+	union := insert.Union(backbone)
+	if !reflect.DeepEqual(union.Names(), []string{"EcoRI", "BsaI", "BamHI", "XbaI"}) {
+		t.Errorf("Expected union [EcoRI BsaI BamHI XbaI], got %v", union.Names())
+	}
+
+	intersection := insert.Intersection(backbone)
+	if !reflect.DeepEqual(intersection.Names(), []string{"BamHI"}) {
+		t.Errorf("Expected intersection [BamHI], got %v", intersection.Names())
+	}
+
+	difference := insert.Difference(backbone)
+	if !reflect.DeepEqual(difference.Names(), []string{"EcoRI", "BsaI"}) {
+		t.Errorf("Expected difference [EcoRI BsaI], got %v", difference.Names())
+	}
+
+	// The operations return new batches and leave the originals unchanged.
+	if len(insert.Enzymes) != 3 || len(backbone.Enzymes) != 2 {
+		t.Errorf("Expected the original batches to be unchanged")
+	}
+}
+
+func TestBatchRemoveAndFilter(t *testing.T) {
+	batch := NewRestrictionBatch(FIXTURES["EcoRI"], FIXTURES["BsaI"], FIXTURES["BamHI"])
+	sequence := "GAATTCAAGGATCC"
+
+	batch.Remove("BamHI", "NotInBatch")
+	if batch.Contains("BamHI") || !batch.Contains("EcoRI") {
+		t.Errorf("Expected BamHI to be removed, got %v", batch.Names())
+	}
+
+	// The site matcher must be rebuilt when enzymes are removed.
+	sites, _ := batch.Search(sequence, false)
+	if _, ok := sites["BamHI"]; ok || len(sites["EcoRI"]) != 1 {
+		t.Errorf("Expected only an EcoRI site, got %v", sites)
+	}
+
+	panel := NewRestrictionBatch(FIXTURES["EcoRI"], FIXTURES["DpnI"])
+	blunt := panel.Filter(func(enzyme Enzyme) bool {
+		return enzyme.CutType == BluntEnd
+	})
+	if !reflect.DeepEqual(blunt.Names(), []string{"DpnI"}) {
+		t.Errorf("Expected [DpnI], got %v", blunt.Names())
+	}
+}
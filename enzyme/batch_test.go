@@ -1,6 +1,10 @@
 package enzyme
 
 import (
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/rmcl/restriction-enzymes/constants"
@@ -69,3 +73,185 @@ func TestSearchBatch(unittest *testing.T) {
 	}
 
 }
+
+func TestFindAllInBatch(unittest *testing.T) {
+	batch := NewRestrictionBatch(
+		FIXTURES["BsaI"],
+		FIXTURES["EcoRI"],
+		FIXTURES["BamHI"],
+		FIXTURES["MflI"],
+		FIXTURES["DpnI"],
+	)
+	sequence := "AGGATCCAAGAATTCAAGAGACCAA"
+
+	results := batch.FindAll(sequence, false)
+
+	expected := []struct {
+		name   string
+		index  int
+		strand constants.Strand
+	}{
+		{"BamHI", 1, constants.Watson},
+		{"MflI", 1, constants.Watson},
+		{"DpnI", 2, constants.Watson},
+		{"EcoRI", 9, constants.Watson},
+		{"BsaI", 17, constants.Crick},
+	}
+
+	if len(results) != len(expected) {
+		unittest.Fatalf("Expected %d results, got %d", len(expected), len(results))
+	}
+	for i, result := range results {
+		if result.Enzyme.Name != expected[i].name || result.RecognitionSiteIndex != expected[i].index || result.Strand != expected[i].strand {
+			unittest.Errorf("Expected %s at %d on %s, got %s at %d on %s",
+				expected[i].name, expected[i].index, expected[i].strand,
+				result.Enzyme.Name, result.RecognitionSiteIndex, result.Strand)
+		}
+	}
+}
+
+// A degenerate site that is not a palindrome can match both strands at the
+// same position. The batch reports the same sites as the enzyme's own search.
+func TestFindAllInBatchMatchesEnzymeForDegenerateSite(t *testing.T) {
+	ez := parseNamedSite(t, "Degenerate", "NGATCY(2/4)")
+	batch := NewRestrictionBatch(ez, FIXTURES["EcoRI"])
+
+	// AGATCC matches both strands, CGATCT only the watson strand and AGATCA
+	// only the crick strand.
+	sequence := "AGATCCTTCGATCTTTAGATCATTGAATTC"
+
+	expected := []RecognitionSiteResult{}
+	for offset := 0; ; {
+		results := ez.GetNextRecognitionSite(sequence, offset, false)
+		if results == nil {
+			break
+		}
+		expected = append(expected, results...)
+		offset = results[0].RecognitionSiteIndex + 1
+	}
+	if len(expected) != 4 || expected[0].Strand != constants.Watson || expected[1].Strand != constants.Crick {
+		t.Fatalf("Expected both strands at 0 and two more sites, got %+v", expected)
+	}
+
+	found := []RecognitionSiteResult{}
+	for _, result := range batch.FindAll(sequence, false) {
+		if result.Enzyme.Name == ez.Name {
+			found = append(found, result)
+		}
+	}
+	if len(found) != len(expected) {
+		t.Fatalf("Expected %d sites, got %d", len(expected), len(found))
+	}
+	for i := range expected {
+		if found[i].RecognitionSiteIndex != expected[i].RecognitionSiteIndex ||
+			found[i].Strand != expected[i].Strand ||
+			found[i].WatsonCutIndex != expected[i].WatsonCutIndex ||
+			found[i].CrickCutIndex != expected[i].CrickCutIndex {
+			t.Errorf("Expected %+v, got %+v", expected[i], found[i])
+		}
+	}
+}
+
+func TestGetNextRecognitionSiteForBatchPrefersEarlierLongerSite(unittest *testing.T) {
+	// The BaeI site starts first but ends after the DpnI site.
+	batch := NewRestrictionBatch(
+		FIXTURES["DpnI"],
+		FIXTURES["BaeI"],
+	)
+	sequence := "TTACGATCGTACCTT"
+
+	results := batch.GetNextRecognitionSite(sequence, 0, false)
+	if len(results) != 1 || results[0].Enzyme.Name != "BaeI" || results[0].RecognitionSiteIndex != 2 {
+		unittest.Fatalf("Expected BaeI at 2, got %v", results)
+	}
+
+	results = batch.GetNextRecognitionSite(sequence, 3, false)
+	if len(results) != 1 || results[0].Enzyme.Name != "DpnI" || results[0].RecognitionSiteIndex != 4 {
+		unittest.Errorf("Expected DpnI at 4, got %v", results)
+	}
+}
+
+func TestSearchBatchCircular(unittest *testing.T) {
+	batch := NewRestrictionBatch(
+		FIXTURES["EcoRI"],
+		FIXTURES["BsaI"],
+	)
+
+	// The EcoRI site spans the origin.
+	sequence := "TTCAAAAAAAAAAGAA"
+
+	linear, _ := batch.Search(sequence, false)
+	if len(linear) != 0 {
+		unittest.Errorf("Expected no sites in the linear sequence, got %v", linear)
+	}
+
+	circular, _ := batch.Search(sequence, true)
+	if len(circular["EcoRI"]) != 1 || circular["EcoRI"][0] != 14 {
+		unittest.Errorf("Expected an EcoRI cut at 14, got %v", circular)
+	}
+}
+
+// Return a reproducible random sequence of the given length.
+func randomSequence(seed int64, length int) string {
+	random := rand.New(rand.NewSource(seed))
+	sequence := make([]byte, length)
+	for i := range sequence {
+		sequence[i] = "ACGT"[random.Intn(4)]
+	}
+	return string(sequence)
+}
+
+// Return a random sequence about the size of a bacterial genome.
+func benchmarkGenome() string {
+	return randomSequence(1, 5_000_000)
+}
+
+// Return a batch of the fixtures, added in order of name so every run
+// searches for the same enzymes in the same order.
+func benchmarkBatch() RestrictionBatch {
+	names := make([]string, 0, len(FIXTURES))
+	for name := range FIXTURES {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	batch := NewRestrictionBatch()
+	for _, name := range names {
+		batch.Add(FIXTURES[name])
+	}
+	return batch
+}
+
+func BenchmarkSearchBatch(b *testing.B) {
+	genome := benchmarkGenome()
+	batch := benchmarkBatch()
+
+	b.Run("Automaton", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			batch.FindAll(genome, true)
+		}
+	})
+
+	// The combined alternation of every enzyme's regexps that the batch
+	// used before the automaton.
+	b.Run("CombinedRegexp", func(b *testing.B) {
+		patterns := []string{}
+		for _, enzyme := range batch.Enzymes {
+			patterns = append(patterns, enzyme.RegexpFor.String(), enzyme.RegexpRev.String())
+		}
+		combinedRegex := regexp.MustCompile("(" + strings.Join(patterns, "|") + ")")
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			offset := 0
+			for {
+				match := combinedRegex.FindStringIndex(genome[offset:])
+				if match == nil {
+					break
+				}
+				offset += match[0] + 1
+			}
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package enzyme
+
+import (
+	"sort"
+	"strings"
+)
+
+// An enzyme whose ends can be ligated to another end.
+type CompatibleEnd struct {
+	Enzyme Enzyme
+
+	// The type and sequence of the overhang left by the compatible enzyme,
+	// read from the watson strand. Bases outside of its recognition site are
+	// written as N.
+	OverhangType     EnzymeOverhangType
+	OverhangSequence string
+
+	// True if the ends are only compatible for some of the concrete bases a
+	// degenerate overhang can have, e.g. the NNNN overhang of BsaI or the
+	// GNC overhang of PspPI. The ends of a real cut must be checked.
+	Degenerate bool
+}
+
+// Return the overhang type and sequence left by the enzyme's first cut.
+func (enzyme *Enzyme) overhang() (EnzymeOverhangType, string) {
+	_, overhangSequence := CalculateOverhang(enzyme.Site, enzyme.FivePrimeCutSite, enzyme.ThreePrimeCutSite)
+	return enzyme.OverhangType(), overhangSequence
+}
+
+// Return the compatible end if the ends left by the other enzyme can be
+// ligated to the ends left by this enzyme. Two blunt ends are always
+// compatible. Sticky ends are compatible if both are 5' or both are 3'
+// overhangs and the overhang bases can pair, in either orientation.
+func (enzyme *Enzyme) CompatibleEnd(other *Enzyme) (CompatibleEnd, bool) {
+	overhangType, overhangSequence := enzyme.overhang()
+	return compatibleEnd(overhangType, overhangSequence, other)
+}
+
+// Return the compatible end if the ends left by the enzyme can be ligated to
+// the concrete ends left by this cut.
+func (result RecognitionSiteResult) CompatibleEnd(other *Enzyme) (CompatibleEnd, bool) {
+	overhangType := result.Enzyme.OverhangType()
+	if overhangType == BluntOverhang || overhangType == UnknownOverhang || result.Overhang == "" {
+		// The overhang bases are unknown if the cut was beyond the end of
+		// a linear sequence, so fall back on the enzyme's overhang.
+		return result.Enzyme.CompatibleEnd(other)
+	}
+	return compatibleEnd(overhangType, result.Overhang, other)
+}
+
+func compatibleEnd(overhangType EnzymeOverhangType, overhangSequence string, other *Enzyme) (CompatibleEnd, bool) {
+	otherType, otherSequence := other.overhang()
+	if overhangType == UnknownOverhang || overhangType != otherType {
+		return CompatibleEnd{}, false
+	}
+
+	compatible := CompatibleEnd{
+		Enzyme:           *other,
+		OverhangType:     otherType,
+		OverhangSequence: otherSequence,
+	}
+	if overhangType == BluntOverhang {
+		return compatible, true
+	}
+
+	pairs, degenerate := overhangsPair(overhangSequence, otherSequence)
+	if !pairs {
+		reversePairs, reverseDegenerate := overhangsPair(overhangSequence, ReverseComplementSite(otherSequence))
+		if !reversePairs {
+			return CompatibleEnd{}, false
+		}
+		degenerate = reverseDegenerate
+	}
+
+	compatible.Degenerate = degenerate
+	return compatible, true
+}
+
+// Return true if the overhangs, both read from the watson strand, can pair.
+// The second return value is true if they can only pair for some of the
+// concrete bases represented by degenerate codes.
+func overhangsPair(overhang string, other string) (bool, bool) {
+	if len(overhang) != len(other) {
+		return false, false
+	}
+
+	degenerate := false
+	for i := 0; i < len(overhang); i++ {
+		bases, ok := IUPACBases(overhang[i])
+		otherBases, otherOk := IUPACBases(other[i])
+		if !ok || !otherOk {
+			return false, false
+		}
+
+		if len(bases) > 1 || len(otherBases) > 1 {
+			degenerate = true
+		}
+		if !strings.ContainsAny(bases, otherBases) {
+			return false, false
+		}
+	}
+	return true, degenerate
+}
+
+// Return the enzymes from the candidates, sorted by name, whose ends can be
+// ligated to the ends left by the enzyme, e.g. BglII and BclI for BamHI.
+// The enzyme itself is not included.
+func CompatibleEnds(enzyme Enzyme, candidates []Enzyme) []CompatibleEnd {
+	return filterCompatibleEnds(enzyme.Name, candidates, enzyme.CompatibleEnd)
+}
+
+// Return the enzymes from the candidates, sorted by name, whose ends can be
+// ligated to the ends left by this cut.
+func (result RecognitionSiteResult) CompatibleEnds(candidates []Enzyme) []CompatibleEnd {
+	return filterCompatibleEnds(result.Enzyme.Name, candidates, result.CompatibleEnd)
+}
+
+func filterCompatibleEnds(name string, candidates []Enzyme, compatibleEnd func(*Enzyme) (CompatibleEnd, bool)) []CompatibleEnd {
+	ends := []CompatibleEnd{}
+	for i := range candidates {
+		if candidates[i].Name == name {
+			continue
+		}
+		if end, ok := compatibleEnd(&candidates[i]); ok {
+			ends = append(ends, end)
+		}
+	}
+
+	sort.Slice(ends, func(i, j int) bool {
+		return ends[i].Enzyme.Name < ends[j].Enzyme.Name
+	})
+	return ends
+}
@@ -0,0 +1,115 @@
+package enzyme
+
+import (
+	"testing"
+)
+
+func parseNamedSite(t *testing.T, name string, notation string) Enzyme {
+	t.Helper()
+	enzyme, err := ParseSite(notation)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %s", notation, err)
+	}
+	enzyme.Name = name
+	return enzyme
+}
+
+func TestCompatibleEnd(t *testing.T) {
+	BamHI := FIXTURES["BamHI"]
+	BglII := parseNamedSite(t, "BglII", "A^GATCT")
+
+	end, ok := BamHI.CompatibleEnd(&BglII)
+	if !ok {
+		t.Fatalf("Expected BglII ends to be compatible with BamHI")
+	}
+	if end.Enzyme.Name != "BglII" || end.OverhangType != FivePrimeOverhang || end.OverhangSequence != "GATC" || end.Degenerate {
+		t.Errorf("Unexpected compatible end %+v", end)
+	}
+
+	SalI := parseNamedSite(t, "SalI", "G^TCGAC")
+	XhoI := parseNamedSite(t, "XhoI", "C^TCGAG")
+	if _, ok := SalI.CompatibleEnd(&XhoI); !ok {
+		t.Errorf("Expected XhoI ends to be compatible with SalI")
+	}
+	if _, ok := SalI.CompatibleEnd(&BamHI); ok {
+		t.Errorf("Expected BamHI ends not to be compatible with SalI")
+	}
+
+	// Both leave TGCA overhangs, but 3' overhangs cannot pair with 5' ones.
+	PstI := parseNamedSite(t, "PstI", "CTGCA^G")
+	PstIFivePrime := parseNamedSite(t, "PstIFivePrime", "C^TGCAG")
+	if _, ok := PstI.CompatibleEnd(&PstIFivePrime); ok {
+		t.Errorf("Expected 3' and 5' overhangs not to be compatible")
+	}
+
+	DpnI := FIXTURES["DpnI"]
+	SmaI := parseNamedSite(t, "SmaI", "CCC^GGG")
+	end, ok = DpnI.CompatibleEnd(&SmaI)
+	if !ok || end.OverhangType != BluntOverhang || end.OverhangSequence != "" {
+		t.Errorf("Expected two blunt ends to be compatible, got %+v", end)
+	}
+	if _, ok := DpnI.CompatibleEnd(&BamHI); ok {
+		t.Errorf("Expected a blunt end not to be compatible with a sticky end")
+	}
+
+	unknown := parseNamedSite(t, "Unknown", "GGATCC")
+	if _, ok := BamHI.CompatibleEnd(&unknown); ok {
+		t.Errorf("Expected an enzyme with unknown cuts not to be compatible")
+	}
+}
+
+func TestCompatibleEndDegenerate(t *testing.T) {
+	// PspPI leaves a GNC overhang that pairs with the GTC overhang of an
+	// AvaII cut at GGTCC, but not with every one of PspPI's own ends.
+	PspPI := FIXTURES["PspPI"]
+	AvaII := parseNamedSite(t, "AvaII", "G^GWCC")
+	GTCCutter := parseNamedSite(t, "GTCCutter", "G^GTCC")
+
+	end, ok := PspPI.CompatibleEnd(&GTCCutter)
+	if !ok || !end.Degenerate || end.OverhangSequence != "GTC" {
+		t.Errorf("Expected a degenerate compatible end, got %+v", end)
+	}
+	if _, ok := AvaII.CompatibleEnd(&GTCCutter); !ok {
+		t.Errorf("Expected GWC and GTC overhangs to be compatible")
+	}
+
+	// A real GGTCC cut is exact.
+	results := PspPI.GetNextRecognitionSite("AAGGTCCAA", 0, false)
+	if len(results) != 1 {
+		t.Fatalf("Expected one PspPI site, got %d", len(results))
+	}
+	end, ok = results[0].CompatibleEnd(&GTCCutter)
+	if !ok || end.Degenerate {
+		t.Errorf("Expected the ends of a GGTCC cut to be exactly compatible, got %+v", end)
+	}
+
+	GACCutter := parseNamedSite(t, "GACCutter", "G^GACC")
+	if _, ok := results[0].CompatibleEnd(&GACCutter); !ok {
+		t.Errorf("Expected GTC and GAC overhangs to pair in reverse orientation")
+	}
+}
+
+func TestCompatibleEnds(t *testing.T) {
+	BamHI := FIXTURES["BamHI"]
+	candidates := []Enzyme{
+		parseNamedSite(t, "BglII", "A^GATCT"),
+		FIXTURES["EcoRI"],
+		BamHI,
+		parseNamedSite(t, "BclI", "T^GATCA"),
+		FIXTURES["DpnI"],
+	}
+
+	ends := CompatibleEnds(BamHI, candidates)
+	if len(ends) != 2 || ends[0].Enzyme.Name != "BclI" || ends[1].Enzyme.Name != "BglII" {
+		t.Errorf("Expected compatible ends [BclI BglII], got %+v", ends)
+	}
+
+	results := BamHI.GetNextRecognitionSite("AAGGATCCAA", 0, false)
+	if len(results) != 1 {
+		t.Fatalf("Expected one BamHI site, got %d", len(results))
+	}
+	ends = results[0].CompatibleEnds(candidates)
+	if len(ends) != 2 || ends[0].Enzyme.Name != "BclI" {
+		t.Errorf("Expected the cut to be compatible with [BclI BglII], got %+v", ends)
+	}
+}
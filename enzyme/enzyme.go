@@ -2,6 +2,7 @@ package enzyme
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/rmcl/restriction-enzymes/constants"
 )
@@ -11,6 +12,7 @@ type EnzymeNumberOfCuts int
 const (
 	OneCut      EnzymeNumberOfCuts = 1
 	TwoCuts     EnzymeNumberOfCuts = 2
+	FourCuts    EnzymeNumberOfCuts = 4
 	UnknownCuts EnzymeNumberOfCuts = 0
 )
 
@@ -50,6 +52,15 @@ type Enzyme struct {
 
 	Uri string
 
+	// The prototype enzyme, the first enzyme found that recognizes the site,
+	// and the isoschizomers listed by REBASE.
+	Prototype     string
+	Isoschizomers []string
+
+	// The methylation line of the REBASE emboss_r file, i.e. the base
+	// modified by the cognate methyltransferase, e.g. "2(6)".
+	Methylation string
+
 	References []string
 }
 
@@ -65,6 +76,38 @@ type RecognitionSiteResult struct {
 	WatsonCutIndex int
 	// The position of the cut site on the watson strand in the sequence.
 	CrickCutIndex int
+
+	// Type IIB enzymes cut on both sides of the recognition site. For these
+	// enzymes the second pair of cut positions is set and HasSecondCut is true.
+	HasSecondCut    bool
+	WatsonCutIndex2 int
+	CrickCutIndex2  int
+
+	// The concrete overhang bases left at this site, read from the watson
+	// strand. Empty for blunt cuts or if the overhang extends beyond the end
+	// of a linear sequence.
+	Overhang  string
+	Overhang2 string
+
+	// The offsets into the recognition site of bases that differ from the
+	// site. Only set by the mismatch tolerant searches.
+	Mismatches []int
+
+	// The effect of methylation on the site. Only set when searching
+	// with a MethylationFilter.
+	MethylationStatus SiteStatus
+}
+
+// Return the watson strand cut positions of the result in ascending order.
+// Type IIB enzymes return two positions, all others one.
+func (result RecognitionSiteResult) WatsonCutIndexes() []int {
+	if !result.HasSecondCut {
+		return []int{result.WatsonCutIndex}
+	}
+	if result.WatsonCutIndex2 < result.WatsonCutIndex {
+		return []int{result.WatsonCutIndex2, result.WatsonCutIndex}
+	}
+	return []int{result.WatsonCutIndex, result.WatsonCutIndex2}
 }
 
 // Get the next recognition site in the sequence after the offset
@@ -114,6 +157,15 @@ func (enzyme *Enzyme) GetNextRecognitionSite(
 	var strand constants.Strand
 	var position int
 
+	if watsonMatchIndex > -1 && watsonMatchIndex == crickMatchIndex && !enzyme.isPalindrome() {
+		// A degenerate site that is not a palindrome matches both strands
+		// at the same position.
+		return []RecognitionSiteResult{
+			newRecognitionSiteResult(enzyme, sequence, watsonMatchIndex, constants.Watson, isCircular),
+			newRecognitionSiteResult(enzyme, sequence, crickMatchIndex, constants.Crick, isCircular),
+		}
+	}
+
 	if watsonMatchIndex > -1 && crickMatchIndex > -1 {
 		if watsonMatchIndex <= crickMatchIndex {
 			strand = constants.Watson
@@ -133,39 +185,110 @@ func (enzyme *Enzyme) GetNextRecognitionSite(
 		return nil
 	}
 
-	watsonIndex, crickIndex := enzyme.GetCutSitePositions(position, strand)
 	return []RecognitionSiteResult{
-		{
-			Enzyme: enzyme,
+		newRecognitionSiteResult(enzyme, sequence, position, strand, isCircular),
+	}
+}
+
+// Build the result for a recognition site of the enzyme found at the given
+// position and strand of the sequence.
+func newRecognitionSiteResult(
+	enzyme *Enzyme,
+	sequence string,
+	position int,
+	strand constants.Strand,
+	isCircular bool,
+) RecognitionSiteResult {
+	watsonIndex, crickIndex := enzyme.GetCutSitePositions(position, strand)
+	result := RecognitionSiteResult{
+		Enzyme: enzyme,
 
-			RecognitionSiteIndex: position,
-			Strand:               strand,
+		RecognitionSiteIndex: position,
+		Strand:               strand,
 
-			WatsonCutIndex: watsonIndex,
-			CrickCutIndex:  crickIndex,
-		},
+		WatsonCutIndex: watsonIndex,
+		CrickCutIndex:  crickIndex,
+
+		Overhang: overhangBases(sequence, watsonIndex, crickIndex, isCircular),
+	}
+
+	if enzyme.HasSecondCut() {
+		result.HasSecondCut = true
+		result.WatsonCutIndex2, result.CrickCutIndex2 = enzyme.GetSecondCutSitePositions(position, strand)
+		result.Overhang2 = overhangBases(sequence, result.WatsonCutIndex2, result.CrickCutIndex2, isCircular)
 	}
+
+	return result
 }
 
 // Given a recognition site index and a strand, return the position of the cut sites
 // on the watson and crick strands. The first int is the watson strand index and the second
 // is the crick strand index.
 func (enzyme *Enzyme) GetCutSitePositions(recognitionSiteIndex int, strand constants.Strand) (int, int) {
+	return enzyme.cutSitePositions(
+		recognitionSiteIndex,
+		strand,
+		enzyme.FivePrimeCutSite,
+		enzyme.ThreePrimeCutSite)
+}
+
+// Return true if the enzyme cuts on both sides of its recognition site
+// (Type IIB). These enzymes have a second pair of cut sites.
+func (enzyme *Enzyme) HasSecondCut() bool {
+	return enzyme.FivePrimeCutSite2 != 0 || enzyme.ThreePrimeCutSite2 != 0
+}
+
+// Return true if the recognition site reads the same on both strands.
+func (enzyme *Enzyme) isPalindrome() bool {
+	site := strings.ToUpper(enzyme.Site)
+	return ReverseComplementSite(site) == site
+}
+
+// Given a recognition site index and a strand, return the position of the second
+// pair of cut sites of a Type IIB enzyme on the watson and crick strands.
+func (enzyme *Enzyme) GetSecondCutSitePositions(recognitionSiteIndex int, strand constants.Strand) (int, int) {
+	return enzyme.cutSitePositions(
+		recognitionSiteIndex,
+		strand,
+		enzyme.FivePrimeCutSite2,
+		enzyme.ThreePrimeCutSite2)
+}
+
+func (enzyme *Enzyme) cutSitePositions(
+	recognitionSiteIndex int,
+	strand constants.Strand,
+	fivePrimeCutSite int,
+	threePrimeCutSite int,
+) (int, int) {
 	var watsonCutIndex, crickCutIndex int
 
+	fivePrimeOffset := rebaseCutOffset(fivePrimeCutSite)
+	threePrimeOffset := rebaseCutOffset(threePrimeCutSite)
+
 	if strand == constants.Watson {
-		watsonCutIndex = recognitionSiteIndex + enzyme.FivePrimeCutSite
-		crickCutIndex = recognitionSiteIndex + enzyme.ThreePrimeCutSite
+		watsonCutIndex = recognitionSiteIndex + fivePrimeOffset
+		crickCutIndex = recognitionSiteIndex + threePrimeOffset
 	} else {
-		watsonCutIndex = recognitionSiteIndex + enzyme.Length - enzyme.ThreePrimeCutSite
-		crickCutIndex = recognitionSiteIndex + enzyme.Length - enzyme.FivePrimeCutSite
+		watsonCutIndex = recognitionSiteIndex + enzyme.Length - threePrimeOffset
+		crickCutIndex = recognitionSiteIndex + enzyme.Length - fivePrimeOffset
 	}
 
 	return watsonCutIndex, crickCutIndex
 }
 
+// REBASE numbers the bases of a site ... -2 -1 1 2 ... with no base zero and
+// cuts to the right of the given base. Convert a REBASE cut position into an
+// offset from the first base of the recognition site.
+func rebaseCutOffset(cutSite int) int {
+	if cutSite < 0 {
+		return cutSite + 1
+	}
+	return cutSite
+}
+
 // Return a list of cut sites for the enzyme. This returns the position of the cut site
-// on the watson strand to mirror Biopython
+// on the watson strand to mirror Biopython. Type IIB enzymes report both cut sites
+// for each recognition site.
 //
 // If isCircular is true, the sequence is treated as circular and the function will
 // return a recognition site even if it spans the beginning and end of the sequence.
@@ -190,7 +313,7 @@ func (enzyme *Enzyme) Search(
 		}
 
 		for _, result := range results {
-			watsonCutSites = append(watsonCutSites, result.WatsonCutIndex)
+			watsonCutSites = append(watsonCutSites, result.WatsonCutIndexes()...)
 
 			lastSitePosition = result.RecognitionSiteIndex + 1
 		}
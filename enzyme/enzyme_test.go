@@ -147,10 +147,77 @@ func TestBsaISearch(t *testing.T) {
 	}
 }
 
+func TestTypeIIBCutSitePositions(t *testing.T) {
+	enzyme := FIXTURES["BaeI"]
+
+	if !enzyme.HasSecondCut() {
+		t.Fatalf("Expected BaeI to have a second cut")
+	}
+
+	// (10/15)ACNNNNGTAYC(12/7)
+	watsonCutIndex, crickCutIndex := enzyme.GetCutSitePositions(20, constants.Watson)
+	if watsonCutIndex != 10 || crickCutIndex != 5 {
+		t.Errorf("Expected first cut at 10, 5, got %d, %d", watsonCutIndex, crickCutIndex)
+	}
+
+	watsonCutIndex, crickCutIndex = enzyme.GetSecondCutSitePositions(20, constants.Watson)
+	if watsonCutIndex != 43 || crickCutIndex != 38 {
+		t.Errorf("Expected second cut at 43, 38, got %d, %d", watsonCutIndex, crickCutIndex)
+	}
+
+	watsonCutIndex, crickCutIndex = enzyme.GetCutSitePositions(20, constants.Crick)
+	if watsonCutIndex != 46 || crickCutIndex != 41 {
+		t.Errorf("Expected first crick cut at 46, 41, got %d, %d", watsonCutIndex, crickCutIndex)
+	}
+
+	watsonCutIndex, crickCutIndex = enzyme.GetSecondCutSitePositions(20, constants.Crick)
+	if watsonCutIndex != 13 || crickCutIndex != 8 {
+		t.Errorf("Expected second crick cut at 13, 8, got %d, %d", watsonCutIndex, crickCutIndex)
+	}
+}
+
+func TestTypeIIBSearch(t *testing.T) {
+	enzyme := FIXTURES["BaeI"]
+
+	padding := strings.Repeat("T", 20)
+
+	results, err := enzyme.Search(padding+"ACAAAAGTACC"+padding, false)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+	if len(results) != 2 || results[0] != 10 || results[1] != 43 {
+		t.Errorf("Expected cuts at [10 43], got %v", results)
+	}
+
+	// The same site on the crick strand
+	results, err = enzyme.Search(padding+"GGTACTTTTGT"+padding, false)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+	if len(results) != 2 || results[0] != 13 || results[1] != 46 {
+		t.Errorf("Expected cuts at [13 46], got %v", results)
+	}
+
+	batch := NewRestrictionBatch(enzyme)
+	batchResults, err := batch.Search(padding+"ACAAAAGTACC"+padding, false)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+	if len(batchResults["BaeI"]) != 2 {
+		t.Errorf("Expected 2 BaeI cuts, got %v", batchResults["BaeI"])
+	}
+}
+
 var FIXTURES = map[string]Enzyme{
-	"BsaI":  {Name: "BsaI", Site: "GGTCTC", Length: 6, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)GGTCTC"), RegexpRev: regexp.MustCompile("(?i)GAGACC"), OverhangLength: 0, OverhangSequence: "", NumberOfCuts: 2, CutType: "sticky", FivePrimeCutSite: 7, ThreePrimeCutSite: 11, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 313, InactivationTemperature: 0, OptimalTemperature: 0, Uri: "https://identifiers.org/rebase:313", References: []string{"Flodman K., Xu S.-Y.;", "Fomenkov A.;", "Kong H., Chen Z.;", "Morgan R.D.;", "Xu S.-Y.;", "Zhu Z., Xu S.-Y.;", "Zhu Z., Xu S.-Y.;"}},
-	"EcoRI": {Name: "EcoRI", Site: "GAATTC", Length: 6, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)GAATTC"), RegexpRev: regexp.MustCompile("(?i)GAATTC"), OverhangLength: 0, OverhangSequence: "", NumberOfCuts: 2, CutType: "sticky", FivePrimeCutSite: 1, ThreePrimeCutSite: 5, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 993, InactivationTemperature: 0, OptimalTemperature: 0, Uri: "https://identifiers.org/rebase:993", References: []string{"Albertsen H.M., Le Paslier D., Abderrahim H., Dausset J., Cann H., ", "Cohen D.;", "Dugaiczyk A., Hedgpeth J., Boyer H.W., Goodman H.M.;", "Flodman K., Xu S.-Y.;", "Forrow S., Lee M., Souhami R.L., Hartley J.A.;", "Greene P.J., Betlach M.C., Boyer H.W., Goodman H.M.;", "Hedgpeth J., Goodman H.M., Boyer H.W.;", "Newman A.K., Rubin R.A., Kim S.H., Modrich P.;", "Pingoud A., Alves J., Fliess A., Geiger R., Rueter T., Wolfes H.;", "Tanaka M.;", "Winkler F.K.;"}},
-	"BamHI": {Name: "BamHI", Site: "GGATCC", Length: 6, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)GGATCC"), RegexpRev: regexp.MustCompile("(?i)GGATCC"), OverhangLength: 0, OverhangSequence: "", NumberOfCuts: 2, CutType: "sticky", FivePrimeCutSite: 1, ThreePrimeCutSite: 5, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 185, InactivationTemperature: 0, OptimalTemperature: 0, Uri: "https://identifiers.org/rebase:185", References: []string{"Brooks J.E., Nathan P.D., Landry D., Sznyter L.A., Waite-Rees P., ", "Ives C.L., Moran L.S., Slatko B.E., Benner J.S.;", "Endo M., Majima T.;", "Flodman K., Xu S.-Y.;", "Fomenkov A.;", "Fomenkov A., Anton B.P., Vincze T., Roberts R.J.;", "Majima T., Endo M.;", "Roberts R.J., Wilson G.A., Young F.E.;", "Usami S., Kurimura H., Kino K., Kamigaki K., Kirimura K.;", "Wilson G.A., Young F.E.;"}},
+	"BsaI":  {Name: "BsaI", Site: "GGTCTC", Length: 6, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)GGTCTC"), RegexpRev: regexp.MustCompile("(?i)GAGACC"), OverhangLength: -4, OverhangSequence: "NNNN", NumberOfCuts: 2, CutType: "sticky", FivePrimeCutSite: 7, ThreePrimeCutSite: 11, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 313, InactivationTemperature: 0, OptimalTemperature: 0, Uri: "https://identifiers.org/rebase:313", References: []string{"Flodman K., Xu S.-Y.;", "Fomenkov A.;", "Kong H., Chen Z.;", "Morgan R.D.;", "Xu S.-Y.;", "Zhu Z., Xu S.-Y.;", "Zhu Z., Xu S.-Y.;"}},
+	"EcoRI": {Name: "EcoRI", Site: "GAATTC", Length: 6, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)GAATTC"), RegexpRev: regexp.MustCompile("(?i)GAATTC"), OverhangLength: -4, OverhangSequence: "AATT", NumberOfCuts: 2, CutType: "sticky", FivePrimeCutSite: 1, ThreePrimeCutSite: 5, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 993, InactivationTemperature: 0, OptimalTemperature: 0, Uri: "https://identifiers.org/rebase:993", References: []string{"Albertsen H.M., Le Paslier D., Abderrahim H., Dausset J., Cann H., ", "Cohen D.;", "Dugaiczyk A., Hedgpeth J., Boyer H.W., Goodman H.M.;", "Flodman K., Xu S.-Y.;", "Forrow S., Lee M., Souhami R.L., Hartley J.A.;", "Greene P.J., Betlach M.C., Boyer H.W., Goodman H.M.;", "Hedgpeth J., Goodman H.M., Boyer H.W.;", "Newman A.K., Rubin R.A., Kim S.H., Modrich P.;", "Pingoud A., Alves J., Fliess A., Geiger R., Rueter T., Wolfes H.;", "Tanaka M.;", "Winkler F.K.;"}},
+	"MflI":  {Name: "MflI", Site: "RGATCY", Length: 6, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)[AG]GATC[CT]"), RegexpRev: regexp.MustCompile("(?i)[AG]GATC[CT]"), OverhangLength: -4, OverhangSequence: "GATC", NumberOfCuts: 2, CutType: "sticky", FivePrimeCutSite: 1, ThreePrimeCutSite: 5, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 1214, Uri: "https://identifiers.org/rebase:1214"},
+	"PspPI": {Name: "PspPI", Site: "GGNCC", Length: 5, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)GG[ACGT]CC"), RegexpRev: regexp.MustCompile("(?i)GG[ACGT]CC"), OverhangLength: -3, OverhangSequence: "GNC", NumberOfCuts: 2, CutType: "sticky", FivePrimeCutSite: 1, ThreePrimeCutSite: 4, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 2771, Uri: "https://identifiers.org/rebase:2771"},
+	"BaeI":  {Name: "BaeI", Site: "ACNNNNGTAYC", Length: 11, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)AC[ACGT][ACGT][ACGT][ACGT]GTA[CT]C"), RegexpRev: regexp.MustCompile("(?i)G[AG]TAC[ACGT][ACGT][ACGT][ACGT]GT"), OverhangLength: 5, OverhangSequence: "NNNNN", NumberOfCuts: 4, CutType: "sticky", FivePrimeCutSite: -11, ThreePrimeCutSite: -16, FivePrimeCutSite2: 23, ThreePrimeCutSite2: 18, RebaseId: 1595, Uri: "https://identifiers.org/rebase:1595"},
+	"XbaI":  {Name: "XbaI", Site: "TCTAGA", Length: 6, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)TCTAGA"), RegexpRev: regexp.MustCompile("(?i)TCTAGA"), OverhangLength: -4, OverhangSequence: "CTAG", NumberOfCuts: 2, CutType: "sticky", FivePrimeCutSite: 1, ThreePrimeCutSite: 5, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 2126, Uri: "https://identifiers.org/rebase:2126"},
+	"DpnI":  {Name: "DpnI", Site: "GATC", Length: 4, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)GATC"), RegexpRev: regexp.MustCompile("(?i)GATC"), OverhangLength: 0, OverhangSequence: "", NumberOfCuts: 2, CutType: "blunt", FivePrimeCutSite: 2, ThreePrimeCutSite: 2, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 776, Uri: "https://identifiers.org/rebase:776"},
+	"ApaI":  {Name: "ApaI", Site: "GGGCCC", Length: 6, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)GGGCCC"), RegexpRev: regexp.MustCompile("(?i)GGGCCC"), OverhangLength: 4, OverhangSequence: "GGCC", NumberOfCuts: 2, CutType: "sticky", FivePrimeCutSite: 5, ThreePrimeCutSite: 1, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 194, Uri: "https://identifiers.org/rebase:194"},
+	"BamHI": {Name: "BamHI", Site: "GGATCC", Length: 6, Substrate: "DNA", RegexpFor: regexp.MustCompile("(?i)GGATCC"), RegexpRev: regexp.MustCompile("(?i)GGATCC"), OverhangLength: -4, OverhangSequence: "GATC", NumberOfCuts: 2, CutType: "sticky", FivePrimeCutSite: 1, ThreePrimeCutSite: 5, FivePrimeCutSite2: 0, ThreePrimeCutSite2: 0, RebaseId: 185, InactivationTemperature: 0, OptimalTemperature: 0, Uri: "https://identifiers.org/rebase:185", References: []string{"Brooks J.E., Nathan P.D., Landry D., Sznyter L.A., Waite-Rees P., ", "Ives C.L., Moran L.S., Slatko B.E., Benner J.S.;", "Endo M., Majima T.;", "Flodman K., Xu S.-Y.;", "Fomenkov A.;", "Fomenkov A., Anton B.P., Vincze T., Roberts R.J.;", "Majima T., Endo M.;", "Roberts R.J., Wilson G.A., Young F.E.;", "Usami S., Kurimura H., Kino K., Kamigaki K., Kirimura K.;", "Wilson G.A., Young F.E.;"}},
 }
 var EXAMPLE_SEQUENCE_1 = "ATGACATAACCGTATTACCGCCATGCATTAGTTATTAATAGTAATCAATTACGGGGTCATTAGTTCATAGCCCATATATGGAGTTCCGCGTTACATAACTTACGGTAAATGGCCCGCCTGGCTGACCGCCCAACGACCCCCGCCCATTGACGTCAATAATGACGTATGTTCCCATAGTAACGCCAATAGGGACTTTCCATTGACGTCAATGGGTGGAGTATTTACGGTAAACTGCCCACTTGGCAGTACATCAAGTGTATCATATGCCAAGTACGCCCCCTATTGACGTCAATGACGGTAAATGGCCCGCCTGGCATTATGCCCAGTACATGACCTTATGGGACTTTCCTACTTGGCAGTACATCTACGTATTAGTCATCGCTATTACCATGGTGATGCGGTTTTGGCAGTACATCAATGGGCGTGGATAGCGGTTTGACTCACGGGGATTTCCAAGTCTCCACCCCATTGACGTCAATGGGAGTTTGTTTTGGCACCAAAATCAACGGGACTTTCCAAAATGTCGTAACAACTCCGCCCCATTGACGCAAATGGGCGGTAGGCGTGTACGGTGGGAGGTCTATATAAGCAGAGCTGGTTTAGTGAACCGTCAGATCCGCTAGTCGACGGTACCTCGGCGATGGCTTTTCCGCCGCGGCGACGGCTGCGCCTCGGTCCCCGCGGCCTCCCGCTTCTTCTCTCGGGACTCCTGCTACCTCTGTGCCGCGCCTTCAACCTAGACGTGGACAGTCCTGCCGAGTACTCTGGCCCCGAGGGAAGTTACTTCGGCTTCGCCGTGGATTTCTTCGTGCCCAGCGCGTCTTCCCGGATGTTTCTTCTCGTGGGAGCTCCCAAAGCAAACACCACCCAGCCTGGGATTGTGGAAGGAGGGCAGGTCCTCAAATGTGACTGGTCTTCTACCCGCCGGTGCCAGCCAATTGAATTTGATGCAACAGGCAATAGAGATTATGCCAAGGATGATCCATTGGAATTTAAGTCCCATCAGTGGTTTGGAGCATCTGTGAGGTCGAAACAGGATAAAATTTTGGCCTGTGCCCCATTGTACCATTGGAGAACTGAGATGAAACAGGAGCGAGAGCCTGTTGGAACATGCTTTCTTCAAGATGGAACAAAGACTGTTGAGTATGCTCCATGTAGATCACAAGATATTGATGCTGATGGACAGGGATTTTGTCAAGGAGGATTCAGCATTGATTTTACTAAAGCTGACAGAGTACTTCTTGGTGGTCCTGGTAGCTTTTATTGGCAAGGTCAGCTTATTTCGGATCAAGTGGCAGAAATCGTATCTAAATACGACCCCAATGTTTACAGCATCAAGTATAATAACCAATTAGCAACTCGGACTGCACAAGCTATTTTTGATGACAGCTATTTGGGTTATTCTGTGGCTGTCGGAGATTTCAATGGTGATGGCATAGATGACTTTGTTTCAGGAGTTCCAAGAGCAGCAAGGACTTTGGGAATGGTTTATATTTATGATGGGAAGAACATGTCCTCCTTATACAATTTTACTGGCGAGCAGATGGCTGCATATTTCGGATTTTCTGTAGCTGCCACTGACATTAATGGAGATGATTATGCAGATGTGTTTATTGGAGCACCTCTCTTCATGGATCGTGGCTCTGATGGCAAACTCCAAGAGGTGGGGCAGGTCTCAGTGTCTCTACAGAGAGCTTCAGGAGACTTCCAGACGACAAAGCTGAATGGATTTGAGGTCTTTGCACGGTTTGGCAGTGCCATAGCTCCTTTGGGAGATCTGGACCAGGATGGTTTCAATGATATTGCAATTGCTGCTCCATATGGGGGTGAAGATAAAAAAGGAATTGTTTATATCTTCAATGGAAGATCAACAGGCTTGAACGCAGTCCCATCTCAAATCCTTGAAGGGCAGTGGGCTGCTCGAAGCATGCCACCAAGCTTTGGCTATTCAATGAAAGGAGCCACAGATATAGACAAAAATGGATATCCAGACTTAATTGTAGGAGCTTTTGGTGTAGATCGAGCTATCTTATACAGGGCCAGACCAGTTATCACTGTAAATGCTGGTCTTGAAGTGTACCCTAGCATTTTAAATCAAGACAATAAAACCTGCTCACTGCCTGGAACAGCTCTCAAAGTTTCCTGTTTTAATGTTAGGTTCTGCTTAAAGGCAGATGGCAAAGGAGTACTTCCCAGGAAACTTAATTTCCAGGTGGAACTTCTTTTGGATAAACTCAAGCAAAAGGGAGCAATTCGACGAGCACTGTTTCTCTACAGCAGGTCCCCAAGTCACTCCAAGAACATGACTATTTCAAGGGGGGGACTGATGCAGTGTGAGGAATTGATAGCGTATCTGCGGGATGAATCTGAATTTAGAGACAAACTCACTCCAATTACTATTTTTATGGAATATCGGTTGGATTATAGAACAGCTGCTGATACAACAGGCTTGCAACCCATTCTTAACCAGTTCACGCCTGCTAACATTAGTCGACAGGCTCACATTCTACTTGACTGTGGTGAAGACAATGTCTGTAAACCCAAGCTGGAAGTTTCTGTAGATAGTGATCAAAAGAAGATCTATATTGGGGATGACAACCCTCTGACATTGATTGTTAAGGCTCAGAATCAAGGAGAAGGTGCCTACGAAGCTGAGCTCATCGTTTCCATTCCACTGCAGGCTGATTTCATCGGGGTTGTCCGAAACAATGAAGCCTTAGCAAGACTTTCCTGTGCATTTAAGACAGAAAACCAAACTCGCCAGGTGGTATGTGACCTTGGAAACCCAATGAAGGCTGGAACTCAACTCTTAGCTGGTCTTCGTTTCAGTGTGCACCAGCAGTCAGAGATGGATACTTCTGTGAAATTTGACTTACAAATCCAAAGCTCAAATCTATTTGACAAAGTAAGCCCAGTTGTATCTCACAAAGTTGATCTTGCTGTTTTAGCTGCAGTTGAGATAAGAGGAGTCTCGAGTCCTGATCATATCTTTCTTCCGATTCCAAACTGGGAGCACAAGGAGAACCCTGAGACTGAAGAAGATGTTGGGCCAGTTGTTCAGCACATCTATGAGCTGAGAAACAATGGTCCAAGTTCATTCAGCAAGGCAATGCTCCATCTTCAGTGGCCTTACAAATATAATAATAACACTCTGTTGTATATCCTTCATTATGATATTGATGGACCAATGAACTGCACTTCAGATATGGAGATCAACCCTTTGAGAATTAAGATCTCATCTTTGCAAACAACTGAAAAGAATGACACGGTTGCCGGGCAAGGTGAGCGGGACCATCTCATCACTAAGCGGGATCTTGCCCTCAGTGAAGGAGATATTCACACTTTGGGTTGTGGAGTTGCTCAGTGCTTGAAGATTGTCTGCCAAGTTGGGAGATTAGACAGAGGAAAGAGTGCAATCTTGTACGTAAAGTCATTACTGTGGACTGAGACTTTTATGAATAAAGAAAATCAGAATCATTCCTATTCTCTGAAGTCGTCTGCTTCATTTAATGTCATAGAGTTTCCTTATAAGAATCTTCCAATTGAGGATATCACCAACTCCACATTGGTTACCACTAATGTCACCTGGGGCATTCAGCCAGCGCCCATGCCTGTGCCTGTGTGGGTGATCATTTTAGCAGTTCTAGCAGGATTGTTGCTACTGGCTGTTTTGGTATTTGTAATGTACAGGATGGGCTTTTTTAAACGGGTCCGGCCACCTCAAGAAGAACAAGAAAGGGAGCAGCTTCAACCTCATGAAAATGGTGAAGGAAACTCAGAAACTCCGGGATCTCGAGCTCAAGCTTCGAATTCTGCAGTCGACGGTACCGCGGGCCCGGGATCCCCACCGGTCGCCACCATGGTGAGCAAGGGCGAGGAGCTGTTCACCGGGGTGGTGCCCATCCTGGTCGAGCTGGACGGCGACGTAAACGGCCACAAGTTCAGCGTGTCCGGCGAGGGCGAGGGCGATGCCACCTACGGCAAGCTGACCCTGAAGTTCATCTGCACCACCGGCAAGCTGCCCGTGCCCTGGCCCACCCTCGTGACCACCTTGACCTACGGCGTGCAGTGCTTCGCCCGCTACCCCGACCACATGAAGCAGCACGACTTCTTCAAGTCCGCCATGCCCGAAGGCTACGTCCAGGAGCGCACCATCTTCTTCAAGGACGACGGCAACTACAAGACCCGCGCCGAGGTGAAGTTCGAGGGCGACACCCTGGTGAACCGCATCGAGCTGAAGGGCATCGACTTCAAGGAGGACGGCAACATCCTGGGGCACAAGCTGGAGTACAACTACAACAGCCACAAGGTCTATATCACCGCCGACAAGCAGAAGAACGGCATCAAGGTGAACTTCAAGACCCGCCACAACATCGAGGACGGCAGCGTGCAGCTCGCCGACCACTACCAGCAGAACACCCCCATCGGCGACGGCCCCGTGCTGCTGCCCGACAACCACTACCTGAGCACCCAGTCCAAGCTGAGCAAAGACCCCAACGAGAAGCGCGATCACATGGTCCTGCTGGAGTTCGTGACCGCCGCCGGGATCACTCTCGGCATGGACGAGCTGTACAAGTAAGCGGCCGCGACTCTAGATCATAATCAGCCATACCACATTTGTAGAGGTTTTACTTGCTTTAAAAAACCTCCCACACCTCCCCCTGAACCTGAAACATAAAATGAATGCAATTGTTGTTGTTAACTTGTTTATTGCAGCTTATAATGGTTACAAATAAAGCAATAGCATCACAAATTTCACAAATAAAGCATTTTTTTCACTGCATTCTAGTTGTGGTTTGTCCAAACTCATCAATGTATCTTAAGGCGTAAATTGTAAGCGTTAATATTTTGTTAAAATTCGCGTTAAATTTTTGTTAAATCAGCTCATTTTTTAACCAATAGGCCGAAATCGGCAAAATCCCTTATAAATCAAAAGAATAGACCGAGATAGGGTTGAGTGTTGTTCCAGTTTGGAACAAGAGTCCACTATTAAAGAACGTGGACTCCAACGTCAAAGGGCGAAAAACCGTCTATCAGGGCGATGGCCCACTACGTGAACCATCACCCTAATCAAGTTTTTTGGGGTCGAGGTGCCGTAAAGCACTAAATCGGAACCCTAAAGGGAGCCCCCGATTTAGAGCTTGACGGGGAAAGCCGGCGAACGTGGCGAGAAAGGAAGGGAAGAAAGCGAAAGGAGCGGGCGCTAGGGCGCTGGCAAGTGTAGCGGTCACGCTGCGCGTAACCACCACACCCGCCGCGCTTAATGCGCCGCTACAGGGCGCGTCAGGTGGCACTTTTCGGGGAAATGTGCGCGGAACCCCTATTTGTTTATTTTTCTAAATACATTCAAATATGTATCCGCTCATGAGACAATAACCCTGATAAATGCTTCAATAATATTGAAAAAGGAAGAGTCCTGAGGCGGAAAGAACCAGCTGTGGAATGTGTGTCAGTTAGGGTGTGGAAAGTCCCCAGGCTCCCCAGCAGGCAGAAGTATGCAAAGCATGCATCTCAATTAGTCAGCAACCAGGTGTGGAAAGTCCCCAGGCTCCCCAGCAGGCAGAAGTATGCAAAGCATGCATCTCAATTAGTCAGCAACCATAGTCCCGCCCCTAACTCCGCCCATCCCGCCCCTAACTCCGCCCAGTTCCGCCCATTCTCCGCCCCATGGCTGACTAATTTTTTTTATTTATGCAGAGGCCGAGGCCGCCTCGGCCTCTGAGCTATTCCAGAAGTAGTGAGGAGGCTTTTTTGGAGGCCTAGGCTTTTGCAAAGATCGATCAAGAGACAGGATGAGGATCGTTTCGCATGATTGAACAAGATGGATTGCACGCAGGTTCTCCGGCCGCTTGGGTGGAGAGGCTATTCGGCTATGACTGGGCACAACAGACAATCGGCTGCTCTGATGCCGCCGTGTTCCGGCTGTCAGCGCAGGGGCGCCCGGTTCTTTTTGTCAAGACCGACCTGTCCGGTGCCCTGAATGAACTGCAAGACGAGGCAGCGCGGCTATCGTGGCTGGCCACGACGGGCGTTCCTTGCGCAGCTGTGCTCGACGTTGTCACTGAAGCGGGAAGGGACTGGCTGCTATTGGGCGAAGTGCCGGGGCAGGATCTCCTGTCATCTCACCTTGCTCCTGCCGAGAAAGTATCCATCATGGCTGATGCAATGCGGCGGCTGCATACGCTTGATCCGGCTACCTGCCCATTCGACCACCAAGCGAAACATCGCATCGAGCGAGCACGTACTCGGATGGAAGCCGGTCTTGTCGATCAGGATGATCTGGACGAAGAGCATCAGGGGCTCGCGCCAGCCGAACTGTTCGCCAGGCTCAAGGCGAGCATGCCCGACGGCGAGGATCTCGTCGTGACCCATGGCGATGCCTGCTTGCCGAATATCATGGTGGAAAATGGCCGCTTTTCTGGATTCATCGACTGTGGCCGGCTGGGTGTGGCGGACCGCTATCAGGACATAGCGTTGGCTACCCGTGATATTGCTGAAGAGCTTGGCGGCGAATGGGCTGACCGCTTCCTCGTGCTTTACGGTATCGCCGCTCCCGATTCGCAGCGCATCGCCTTCTATCGCCTTCTTGACGAGTTCTTCTGAGCGGGACTCTGGGGTTCGAAATGACCGACCAAGCGACGCCCAACCTGCCATCACGAGATTTCGATTCCACCGCCGCCTTCTATGAAAGGTTGGGCTTCGGAATCGTTTTCCGGGACGCCGGCTGGATGATCCTCCAGCGCGGGGATCTCATGCTGGAGTTCTTCGCCCACCCTAGGGGGAGGCTAACTGAAACACGGAAGGAGACAATACCGGAAGGAACCCGCGCTATGACGGCAATAAAAAGACAGAATAAAACGCACGGTGTTGGGTCGTTTGTTCATAAACGCGGGGTTCGGTCCCAGGGCTGGCACTCTGTCGATACCCCACCGAGACCCCATTGGGGCCAATACGCCCGCGTTTCTTCCTTTTCCCCACCCCACCCCCCAAGTTCGGGTGAAGGCCCAGGGCTCGCAGCCAACGTCGGGGCGGCAGGCCCTGCCATAGCCTCAGGTTACTCATATATACTTTAGATTGATTTAAAACTTCATTTTTAATTTAAAAGGATCTAGGTGAAGATCCTTTTTGATAATCTCATGACCAAAATCCCTTAACGTGAGTTTTCGTTCCACTGAGCGTCAGACCCCGTAGAAAAGATCAAAGGATCTTCTTGAGATCCTTTTTTTCTGCGCGTAATCTGCTGCTTGCAAACAAAAAAACCACCGCTACCAGCGGTGGTTTGTTTGCCGGATCAAGAGCTACCAACTCTTTTTCCGAAGGTAACTGGCTTCAGCAGAGCGCAGATACCAAATACTGTTCTTCTAGTGTAGCCGTAGTTAGGCCACCACTTCAAGAACTCTGTAGCACCGCCTACATACCTCGCTCTGCTAATCCTGTTACCAGTGGCTGCTGCCAGTGGCGATAAGTCGTGTCTTACCGGGTTGGACTCAAGACGATAGTTACCGGATAAGGCGCAGCGGTCGGGCTGAACGGGGGGTTCGTGCACACAGCCCAGCTTGGAGCGAACGACCTACACCGAACTGAGATACCTACAGCGTGAGCTATGAGAAAGCGCCACGCTTCCCGAAGGGAGAAAGGCGGACAGGTATCCGGTAAGCGGCAGGGTCGGAACAGGAGAGCGCACGAGGGAGCTTCCAGGGGGAAACGCCTGGTATCTTTATAGTCCTGTCGGGTTTCGCCACCTCTGACTTGAGCGTCGATTTTTGTGATGCTCGTCAGGGGGGCGGAGCCTATGGAAAAACGCCAGCAACGCGGCCTTTTTACGGTTCCTGGCCTTTTGCTGGCCTTTTGCTCACATGTTCTTTCCTGCGTTATCCCCTGATTCTGTGGAA"
 var EXAMPLE_SEQUENCE_2 = "ttacggggtcattagttcatagcccatatatggagttccgcgttacataacttacggtaaatggcccgcctggctgaccgcccaacgacccccgcccattgacgtcaataatgacgtatgttcccatagtaacgccaatagggactttccattgacgtcaatgggtggagtatttacggtaaactgcccacttggcagtacatcaagtgtatcatatgccaagtacgccccctattgacgtcaatgacggtaaatggcccgcctggcattatgcccagtacatgaccttatgggactttcctacttggcagtacatctacgtattagtcatcgctattaccatggtgatgcggttttggcagtacatcaatgggcgtggatagcggtttgactcacggggatttccaagtctccaccccattgacgtcaatgggagtttgttttggcaccaaaatcaacgggactttccaaaatgtcgtaacaactccgccccattgacgcaaatgggcggtaggcgtgtacggtgggaggtctatataagcagagctggtttagtgaaccgtcagatccgctagcatgaggcttcgggagccgctcctgagcggcagcgccgcgatgccaggcgcgtccctacagcgggcctgccgcctgctcgtggccgtctgcgctctgcaccttggcgtcaccctcgtttactacctggctggccgcgacctgagccgcctgccccaactggtcggagtctccacaccgctgcagggcggctcgaacagtgccgccgccatcgggcagtcctccggggagctccggaccggaggggccaaggatccaccggtcgccaccatggtgagcaagggcgaggagctgttcaccggggtggtgcccatcctggtcgagctggacggcgacgtaaacggccacaagttcagcgtgtccggcgagggcgagggcgatgccacctacggcaagctgaccctgaagttcatctgcaccaccggcaagctgcccgtgccctggcccaccctcgtgaccaccttgacctacggcgtgcagtgcttcgcccgctaccccgaccacatgaagcagcacgacttcttcaagtccgccatgcccgaaggctacgtccaggagcgcaccatcttcttcaaggacgacggcaactacaagacccgcgccgaggtgaagttcgagggcgacaccctggtgaaccgcatcgagctgaagggcatcgacttcaaggaggacggcaacatcctggggcacaagctggagtacaactacaacagccacaaggtctatatcaccgccgacaagcagaagaacggcatcaaggtgaacttcaagacccgccacaacatcgaggacggcagcgtgcagctcgccgaccactaccagcagaacacccccatcggcgacggccccgtgctgctgcccgacaaccactacctgagcacccagtccaagctgagcaaagaccccaacgagaagcgcgatcacatggtcctgctggagttcgtgaccgccgccgggatcactctcggcatggacgagctgtacaagtaagcggccgcgactctagatcataatcagccataccacatttgtagaggttttacttgctttaaaaaacctcccacacctccccctgaacctgaaacataaaatgaatgcaattgttgttgttaacttgtttattgcagcttataatggttacaaataaagcaatagcatcacaaatttcacaaataaagcatttttttcactgcattctagttgtggtttgtccaaactcatcaatgtatcttaaggcgtaaattgtaagcgttaatattttgttaaaattcgcgttaaatttttgttaaatcagctcattttttaaccaataggccgaaatcggcaaaatcccttataaatcaaaagaatagaccgagatagggttgagtgttgttccagtttggaacaagagtccactattaaagaacgtggactccaacgtcaaagggcgaaaaaccgtctatcagggcgatggcccactacgtgaaccatcaccctaatcaagttttttggggtcgaggtgccgtaaagcactaaatcggaaccctaaagggagcccccgatttagagcttgacggggaaagccggcgaacgtggcgagaaaggaagggaagaaagcgaaaggagcgggcgctagggcgctggcaagtgtagcggtcacgctgcgcgtaaccaccacacccgccgcgcttaatgcgccgctacagggcgcgtcaggtggcacttttcggggaaatgtgcgcggaacccctatttgtttatttttctaaatacattcaaatatgtatccgctcatgagacaataaccctgataaatgcttcaataatattgaaaaaggaagagtcctgaggcggaaagaaccagctgtggaatgtgtgtcagttagggtgtggaaagtccccaggctccccagcaggcagaagtatgcaaagcatgcatctcaattagtcagcaaccaggtgtggaaagtccccaggctccccagcaggcagaagtatgcaaagcatgcatctcaattagtcagcaaccatagtcccgcccctaactccgcccatcccgcccctaactccgcccagttccgcccattctccgccccatggctgactaattttttttatttatgcagaggccgaggccgcctcggcctctgagctattccagaagtagtgaggaggcttttttggaggcctaggcttttgcaaagatcgatcaagagacaggatgaggatcgtttcgcatgattgaacaagatggattgcacgcaggttctccggccgcttgggtggagaggctattcggctatgactgggcacaacagacaatcggctgctctgatgccgccgtgttccggctgtcagcgcaggggcgcccggttctttttgtcaagaccgacctgtccggtgccctgaatgaactgcaagacgaggcagcgcggctatcgtggctggccacgacgggcgttccttgcgcagctgtgctcgacgttgtcactgaagcgggaagggactggctgctattgggcgaagtgccggggcaggatctcctgtcatctcaccttgctcctgccgagaaagtatccatcatggctgatgcaatgcggcggctgcatacgcttgatccggctacctgcccattcgaccaccaagcgaaacatcgcatcgagcgagcacgtactcggatggaagccggtcttgtcgatcaggatgatctggacgaagagcatcaggggctcgcgccagccgaactgttcgccaggctcaaggcgagcatgcccgacggcgaggatctcgtcgtgacccatggcgatgcctgcttgccgaatatcatggtggaaaatggccgcttttctggattcatcgactgtggccggctgggtgtggcggaccgctatcaggacatagcgttggctacccgtgatattgctgaagagcttggcggcgaatgggctgaccgcttcctcgtgctttacggtatcgccgctcccgattcgcagcgcatcgccttctatcgccttcttgacgagttcttctgagcgggactctggggttcgaaatgaccgaccaagcgacgcccaacctgccatcacgagatttcgattccaccgccgccttctatgaaaggttgggcttcggaatcgttttccgggacgccggctggatgatcctccagcgcggggatctcatgctggagttcttcgcccaccctagggggaggctaactgaaacacggaaggagacaataccggaaggaacccgcgctatgacggcaataaaaagacagaataaaacgcacggtgttgggtcgtttgttcataaacgcggggttcggtcccagggctggcactctgtcgataccccaccgagaccccattggggccaatacgcccgcgtttcttccttttccccaccccaccccccaagttcgggtgaaggcccagggctcgcagccaacgtcggggcggcaggccctgccatagcctcaggttactcatatatactttagattgatttaaaacttcatttttaatttaaaaggatctaggtgaagatcctttttgataatctcatgaccaaaatcccttaacgtgagttttcgttccactgagcgtcagaccccgtagaaaagatcaaaggatcttcttgagatcctttttttctgcgcgtaatctgctgcttgcaaacaaaaaaaccaccgctaccagcggtggtttgtttgccggatcaagagctaccaactctttttccgaaggtaactggcttcagcagagcgcagataccaaatactgttcttctagtgtagccgtagttaggccaccacttcaagaactctgtagcaccgcctacatacctcgctctgctaatcctgttaccagtggctgctgccagtggcgataagtcgtgtcttaccgggttggactcaagacgatagttaccggataaggcgcagcggtcgggctgaacggggggttcgtgcacacagcccagcttggagcgaacgacctacaccgaactgagatacctacagcgtgagctatgagaaagcgccacgcttcccgaagggagaaaggcggacaggtatccggtaagcggcagggtcggaacaggagagcgcacgagggagcttccagggggaaacgcctggtatctttatagtcctgtcgggtttcgccacctctgacttgagcgtcgatttttgtgatgctcgtcaggggggcggagcctatggaaaaacgccagcaacgcggcctttttacggttcctggccttttgctggccttttgctcacatgttctttcctgcgttatcccctgattctgtggataaccgtattaccgccatgcattagttattaatagtaatcaa"
@@ -0,0 +1,164 @@
+package enzyme
+
+import (
+	"math"
+	"strings"
+)
+
+// The expected and, for a real sequence, observed number of recognition
+// sites of an enzyme.
+type CutFrequency struct {
+	Enzyme string
+
+	// The probability that a site starts at any one position of a random
+	// sequence, on either strand.
+	SiteProbability float64
+
+	// The expected number of sites in the sequence and the mean distance
+	// between them.
+	ExpectedSites      float64
+	MeanFragmentLength float64
+
+	// The number of sites found in a real sequence, the ratio of observed to
+	// expected sites and the probability of finding this few sites or fewer
+	// by chance. A small probability flags a site that is under-represented,
+	// e.g. avoided by a restriction-modification system. Only set when
+	// comparing to a sequence.
+	ObservedSites     int
+	ObservedRatio     float64
+	UnderRepresentedP float64
+}
+
+// Return the probability of each base in a random sequence with the given
+// GC content, a fraction between 0 and 1.
+func baseProbabilities(gcContent float64) map[byte]float64 {
+	return map[byte]float64{
+		'A': (1 - gcContent) / 2,
+		'T': (1 - gcContent) / 2,
+		'G': gcContent / 2,
+		'C': gcContent / 2,
+	}
+}
+
+// Return the probability that the enzyme's site starts at any one position
+// of a random sequence with the given GC content. Degenerate bases match
+// each of the bases they represent. Sites that are not palindromes can be
+// found on either strand, but a position matching both strands counts once.
+func (enzyme *Enzyme) SiteProbability(gcContent float64) float64 {
+	probabilities := baseProbabilities(gcContent)
+
+	forwardSite := strings.ToUpper(enzyme.Site)
+	reverseSite := ReverseComplementSite(enzyme.Site)
+	if forwardSite == "" {
+		return 0
+	}
+
+	forward, reverse, both := 1.0, 1.0, 1.0
+	for i := 0; i < len(forwardSite); i++ {
+		var forwardBase, reverseBase, bothBase float64
+		for base, probability := range probabilities {
+			matchesForward := IUPACMatch(forwardSite[i], base)
+			matchesReverse := IUPACMatch(reverseSite[i], base)
+			if matchesForward {
+				forwardBase += probability
+			}
+			if matchesReverse {
+				reverseBase += probability
+			}
+			if matchesForward && matchesReverse {
+				bothBase += probability
+			}
+		}
+		forward *= forwardBase
+		reverse *= reverseBase
+		both *= bothBase
+	}
+
+	return forward + reverse - both
+}
+
+// Return the expected number of sites of the enzyme, and the mean fragment
+// length, in a random sequence of the given length and GC content.
+func (enzyme *Enzyme) ExpectedFrequency(length int, gcContent float64, isCircular bool) CutFrequency {
+	probability := enzyme.SiteProbability(gcContent)
+
+	positions := length
+	if !isCircular {
+		positions = max(length-enzyme.Length+1, 0)
+	}
+
+	frequency := CutFrequency{
+		Enzyme:             enzyme.Name,
+		SiteProbability:    probability,
+		ExpectedSites:      probability * float64(positions),
+		MeanFragmentLength: math.Inf(1),
+	}
+	if probability > 0 {
+		frequency.MeanFragmentLength = 1 / probability
+	}
+	return frequency
+}
+
+// Return the expected frequency of every enzyme in the batch in a random
+// sequence of the given length and GC content, in the order of the batch.
+func (restrictionBatch *RestrictionBatch) ExpectedFrequencies(length int, gcContent float64, isCircular bool) []CutFrequency {
+	frequencies := make([]CutFrequency, len(restrictionBatch.Enzymes))
+	for i := range restrictionBatch.Enzymes {
+		frequencies[i] = restrictionBatch.Enzymes[i].ExpectedFrequency(length, gcContent, isCircular)
+	}
+	return frequencies
+}
+
+// Compare the number of sites of every enzyme in the batch found in the
+// sequence to the number expected in a random sequence of the same length
+// and GC content. The frequencies are in the order of the batch.
+func (restrictionBatch *RestrictionBatch) CompareFrequencies(sequence string, isCircular bool) []CutFrequency {
+	observed := map[string]int{}
+	for _, result := range restrictionBatch.FindAll(sequence, isCircular) {
+		observed[result.Enzyme.Name]++
+	}
+
+	frequencies := restrictionBatch.ExpectedFrequencies(len(sequence), GCContent(sequence), isCircular)
+	for i := range frequencies {
+		frequency := &frequencies[i]
+		frequency.ObservedSites = observed[frequency.Enzyme]
+		if frequency.ExpectedSites > 0 {
+			frequency.ObservedRatio = float64(frequency.ObservedSites) / frequency.ExpectedSites
+		}
+		frequency.UnderRepresentedP = poissonCDF(frequency.ObservedSites, frequency.ExpectedSites)
+	}
+	return frequencies
+}
+
+// Return the fraction of the concrete bases in the sequence that are G or C.
+func GCContent(sequence string) float64 {
+	gc, total := 0, 0
+	for i := 0; i < len(sequence); i++ {
+		switch upperBase(sequence[i]) {
+		case 'G', 'C':
+			gc++
+			total++
+		case 'A', 'T', 'U':
+			total++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(gc) / float64(total)
+}
+
+// Return the probability of k or fewer events from a Poisson distribution
+// with mean lambda.
+func poissonCDF(k int, lambda float64) float64 {
+	if lambda <= 0 {
+		return 1
+	}
+
+	cumulative := 0.0
+	for i := 0; i <= k; i++ {
+		logFactorial, _ := math.Lgamma(float64(i + 1))
+		cumulative += math.Exp(-lambda + float64(i)*math.Log(lambda) - logFactorial)
+	}
+	return math.Min(cumulative, 1)
+}
@@ -0,0 +1,77 @@
+package enzyme
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSiteProbability(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		expected float64
+	}{
+		// A palindrome is only counted once.
+		{"EcoRI", 1.0 / 4096},
+		// A site that is not a palindrome can be found on either strand.
+		{"BsaI", 2.0 / 4096},
+		// Degenerate bases match more than one base.
+		{"MflI", 1.0 / 1024},
+		{"PspPI", 1.0 / 256},
+	}
+
+	for _, test := range tests {
+		enzyme := FIXTURES[test.fixture]
+		if probability := enzyme.SiteProbability(0.5); math.Abs(probability-test.expected) > 1e-12 {
+			t.Errorf("Expected a site probability of %g for %s, got %g", test.expected, test.fixture, probability)
+		}
+	}
+
+	// GC rich sites are rarer in AT rich sequence.
+	ApaI := FIXTURES["ApaI"]
+	if ApaI.SiteProbability(0.3) >= ApaI.SiteProbability(0.5) {
+		t.Errorf("Expected ApaI sites to be rarer in AT rich sequence")
+	}
+}
+
+func TestExpectedFrequency(t *testing.T) {
+	EcoRI := FIXTURES["EcoRI"]
+	frequency := EcoRI.ExpectedFrequency(4096, 0.5, true)
+
+	if math.Abs(frequency.ExpectedSites-1) > 1e-9 || math.Abs(frequency.MeanFragmentLength-4096) > 1e-6 {
+		t.Errorf("Expected 1 site and fragments of 4096, got %g and %g", frequency.ExpectedSites, frequency.MeanFragmentLength)
+	}
+}
+
+func TestCompareFrequencies(t *testing.T) {
+	batch := NewRestrictionBatch(FIXTURES["EcoRI"], FIXTURES["BamHI"])
+	sequence := randomSequence(7, 200_000)
+
+	// Remove every BamHI site as a restriction-modification system would.
+	sequence = strings.ReplaceAll(sequence, "GGATCC", "GGATCA")
+
+	frequencies := batch.CompareFrequencies(sequence, false)
+	if len(frequencies) != 2 || frequencies[0].Enzyme != "EcoRI" || frequencies[1].Enzyme != "BamHI" {
+		t.Fatalf("Expected EcoRI and BamHI frequencies, got %v", frequencies)
+	}
+
+	EcoRI := frequencies[0]
+	EcoRISites, _ := batch.Search(sequence, false)
+	if EcoRI.ObservedSites != len(EcoRISites["EcoRI"]) {
+		t.Errorf("Expected %d observed EcoRI sites, got %d", len(EcoRISites["EcoRI"]), EcoRI.ObservedSites)
+	}
+	if EcoRI.ObservedRatio < 0.6 || EcoRI.ObservedRatio > 1.4 || EcoRI.UnderRepresentedP < 0.01 {
+		t.Errorf("Expected EcoRI sites at about the expected frequency, got %v", EcoRI)
+	}
+
+	BamHI := frequencies[1]
+	if BamHI.ObservedSites != 0 || BamHI.UnderRepresentedP > 1e-6 {
+		t.Errorf("Expected BamHI sites to be under-represented, got %v", BamHI)
+	}
+}
+
+func TestGCContent(t *testing.T) {
+	if gc := GCContent("GGCCATNN"); math.Abs(gc-4.0/6) > 1e-12 {
+		t.Errorf("Expected a GC content of 0.667, got %g", gc)
+	}
+}
@@ -0,0 +1,161 @@
+package enzyme
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bebop/poly/transform"
+)
+
+// A mapping of IUPAC nucleotide codes to the concrete bases they represent.
+var iupacBases = map[byte]string{
+	'A': "A",
+	'C': "C",
+	'G': "G",
+	'T': "T",
+	'U': "T",
+	'R': "AG",
+	'Y': "CT",
+	'S': "CG",
+	'W': "AT",
+	'K': "GT",
+	'M': "AC",
+	'B': "CGT",
+	'D': "AGT",
+	'H': "ACT",
+	'V': "ACG",
+	'N': "ACGT",
+}
+
+// Return the concrete bases represented by an IUPAC nucleotide code. The
+// second return value is false if the code is not a valid IUPAC code.
+func IUPACBases(code byte) (string, bool) {
+	bases, ok := iupacBases[upperBase(code)]
+	return bases, ok
+}
+
+// Return true if the concrete base is one of the bases represented by
+// the IUPAC code. The comparison is case insensitive.
+func IUPACMatch(code byte, base byte) bool {
+	bases, ok := iupacBases[upperBase(code)]
+	if !ok {
+		return false
+	}
+	return strings.IndexByte(bases, upperBase(base)) >= 0
+}
+
+// Return true if the site contains any degenerate IUPAC codes (anything
+// other than A, C, G or T).
+func IsDegenerate(site string) bool {
+	for i := 0; i < len(site); i++ {
+		if len(iupacBases[upperBase(site[i])]) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the reverse complement of a recognition site. Degenerate IUPAC
+// codes are complemented as well, e.g. RGATCY -> RGATCY.
+func ReverseComplementSite(site string) string {
+	return transform.ReverseComplement(strings.ToUpper(site))
+}
+
+// Convert a recognition site written with IUPAC nucleotide codes into a
+// regular expression pattern. Degenerate codes are expanded into character
+// classes, e.g. RGATCY -> [AG]GATC[CT].
+func SitePattern(site string) (string, error) {
+	var pattern strings.Builder
+	pattern.WriteString("(?i)")
+
+	for i := 0; i < len(site); i++ {
+		bases, ok := iupacBases[upperBase(site[i])]
+		if !ok {
+			return "", fmt.Errorf("invalid IUPAC code %q in site %s", site[i], site)
+		}
+
+		if len(bases) == 1 {
+			pattern.WriteString(bases)
+		} else {
+			pattern.WriteString("[" + bases + "]")
+		}
+	}
+
+	return pattern.String(), nil
+}
+
+// Compile a recognition site written with IUPAC nucleotide codes into a
+// case insensitive regular expression.
+func CompileSite(site string) (*regexp.Regexp, error) {
+	pattern, err := SitePattern(site)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(pattern)
+}
+
+// Compile the forward and reverse complement regular expressions for a
+// recognition site. The first regexp matches the site on the watson strand
+// and the second matches it on the crick strand.
+func CompileSiteRegexps(site string) (*regexp.Regexp, *regexp.Regexp, error) {
+	regexpFor, err := CompileSite(site)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	regexpRev, err := CompileSite(ReverseComplementSite(site))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return regexpFor, regexpRev, nil
+}
+
+// Return true if the IUPAC site matches the sequence at the position. If the
+// sequence is circular positions wrap around the origin.
+func matchesAt(sequence string, site string, position int, isCircular bool) bool {
+	_, ok := siteMismatches(sequence, site, position, isCircular, 0)
+	return ok
+}
+
+// Compare the IUPAC site to the sequence at the position and return the
+// offsets into the site of every base that does not match. The second return
+// value is false if there are more than maxMismatches mismatches or the site
+// extends beyond the end of a linear sequence.
+func siteMismatches(
+	sequence string,
+	site string,
+	position int,
+	isCircular bool,
+	maxMismatches int,
+) ([]int, bool) {
+	if len(sequence) == 0 {
+		return nil, false
+	}
+
+	var mismatches []int
+	for i := 0; i < len(site); i++ {
+		index := position + i
+		if isCircular {
+			index = ((index % len(sequence)) + len(sequence)) % len(sequence)
+		} else if index < 0 || index >= len(sequence) {
+			return nil, false
+		}
+
+		if !IUPACMatch(site[i], sequence[index]) {
+			mismatches = append(mismatches, i)
+			if len(mismatches) > maxMismatches {
+				return nil, false
+			}
+		}
+	}
+	return mismatches, true
+}
+
+func upperBase(base byte) byte {
+	if base >= 'a' && base <= 'z' {
+		return base - ('a' - 'A')
+	}
+	return base
+}
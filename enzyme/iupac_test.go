@@ -0,0 +1,99 @@
+package enzyme
+
+import (
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+)
+
+func TestSitePattern(t *testing.T) {
+	cases := map[string]string{
+		"GAATTC":       "(?i)GAATTC",
+		"RGATCY":       "(?i)[AG]GATC[CT]",
+		"GGNCC":        "(?i)GG[ACGT]CC",
+		"ccwwgg":       "(?i)CC[AT][AT]GG",
+		"GACNNNNNNGTC": "(?i)GAC[ACGT][ACGT][ACGT][ACGT][ACGT][ACGT]GTC",
+	}
+
+	for site, expected := range cases {
+		pattern, err := SitePattern(site)
+		if err != nil {
+			t.Errorf("Error compiling site %s: %v", site, err)
+			continue
+		}
+		if pattern != expected {
+			t.Errorf("Expected %s for site %s, got %s", expected, site, pattern)
+		}
+	}
+
+	if _, err := SitePattern("GAXTTC"); err == nil {
+		t.Errorf("Expected an error for an invalid IUPAC code")
+	}
+}
+
+func TestCompileSiteRegexps(t *testing.T) {
+	regexpFor, regexpRev, err := CompileSiteRegexps("GGTCTCN")
+	if err != nil {
+		t.Fatalf("Error compiling site: %v", err)
+	}
+
+	if !regexpFor.MatchString("aaggtctcaa") {
+		t.Errorf("Expected forward regexp to match ggtctca")
+	}
+	if !regexpRev.MatchString("aatgagaccaa") {
+		t.Errorf("Expected reverse regexp to match tgagacc")
+	}
+	if regexpFor.MatchString("GGTCTCN") {
+		t.Errorf("Expected forward regexp not to match a literal N")
+	}
+}
+
+func TestDegenerateEnzymeSearch(t *testing.T) {
+	enzyme := FIXTURES["MflI"]
+
+	// AGATCC, GGATCT and GGATCC are all MflI sites.
+	sequence := "TTAGATCCTTTTGGATCTTTTTGGATCCTT"
+	results, err := enzyme.Search(sequence, false)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+
+	expected := []int{3, 13, 23}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d MflI sites, got %d: %v", len(expected), len(results), results)
+	}
+	for i, position := range expected {
+		if results[i] != position {
+			t.Errorf("Expected cut at %d, got %d", position, results[i])
+		}
+	}
+}
+
+func TestDegenerateBatchSearch(t *testing.T) {
+	batch := NewRestrictionBatch(
+		FIXTURES["BamHI"],
+		FIXTURES["MflI"],
+		FIXTURES["PspPI"],
+	)
+
+	// GGATCC is recognized by both BamHI and MflI, GGTCC by PspPI.
+	sequence := "TTTTGGATCCTTTTTGGTCCTTTT"
+
+	results := batch.GetNextRecognitionSite(sequence, 0, false)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	for _, result := range results {
+		if result.RecognitionSiteIndex != 4 || result.Strand != constants.Watson {
+			t.Errorf("Expected watson site at 4 for %s, got %d %s", result.Enzyme.Name, result.RecognitionSiteIndex, result.Strand)
+		}
+	}
+
+	sites, err := batch.Search(sequence, false)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+	if len(sites["BamHI"]) != 1 || len(sites["MflI"]) != 1 || len(sites["PspPI"]) != 1 {
+		t.Errorf("Expected one site for each enzyme, got %v", sites)
+	}
+}
@@ -0,0 +1,289 @@
+package enzyme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+)
+
+// The junction formed by ligating the end of a fragment cut by one enzyme to
+// the start of a fragment cut by another. The sequence only covers the bases
+// known from the two parent recognition sites and cuts. Bases between a site
+// and a cut outside of it are written as N and the overhang is written with
+// the IUPAC codes both ends have in common.
+type LigationJunction struct {
+	// The enzymes that cut the fragments on the left and right of the
+	// junction.
+	Left  string
+	Right string
+
+	// The watson strand sequence of the junction and the index of the
+	// watson strand ligation point in it.
+	Sequence      string
+	LigationIndex int
+
+	OverhangType EnzymeOverhangType
+	Overhang     string
+
+	// The recognition sites of the parent enzymes, and of the enzymes of the
+	// batch the junction was checked with, found in the junction sequence.
+	Sites []JunctionSite
+}
+
+// A recognition site found in a ligation junction.
+type JunctionSite struct {
+	Enzyme               string
+	RecognitionSiteIndex int
+	Strand               constants.Strand
+
+	// True if every base the junction can have matches the site. Otherwise
+	// the site is only formed for some of the bases represented by N or
+	// another degenerate code in the junction.
+	Certain bool
+}
+
+// Return true if the junction is certainly re-cut by the enzyme.
+func (junction *LigationJunction) IsRecutBy(name string) bool {
+	for _, site := range junction.Sites {
+		if site.Enzyme == name && site.Certain {
+			return true
+		}
+	}
+	return false
+}
+
+// Return true if the junction can be re-cut by the enzyme for some of the
+// bases represented by degenerate codes in the junction.
+func (junction *LigationJunction) MayBeRecutBy(name string) bool {
+	for _, site := range junction.Sites {
+		if site.Enzyme == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Return true if the junction regenerates the recognition site of either
+// parent enzyme.
+func (junction *LigationJunction) RegeneratesParentSite() bool {
+	return junction.IsRecutBy(junction.Left) || junction.IsRecutBy(junction.Right)
+}
+
+// The recognition site and cuts of an enzyme as seen from the watson strand,
+// with the site starting at index zero.
+type cutSiteContext struct {
+	site         string
+	watsonCut    int
+	crickCut     int
+	lowerCut     int
+	higherCut    int
+	overhangType EnzymeOverhangType
+}
+
+func newCutSiteContext(enzyme *Enzyme, strand constants.Strand) cutSiteContext {
+	site := strings.ToUpper(enzyme.Site)
+	if strand == constants.Crick {
+		site = ReverseComplementSite(enzyme.Site)
+	}
+
+	watsonCut, crickCut := enzyme.GetCutSitePositions(0, strand)
+	return cutSiteContext{
+		site:         site,
+		watsonCut:    watsonCut,
+		crickCut:     crickCut,
+		lowerCut:     min(watsonCut, crickCut),
+		higherCut:    max(watsonCut, crickCut),
+		overhangType: enzyme.OverhangType(),
+	}
+}
+
+// Return the bases of the site from start (inclusive) to end (exclusive).
+// Positions outside of the site are written as N.
+func siteWindow(site string, start int, end int) string {
+	window := make([]byte, 0, max(end-start, 0))
+	for i := start; i < end; i++ {
+		if i >= 0 && i < len(site) {
+			window = append(window, site[i])
+		} else {
+			window = append(window, 'N')
+		}
+	}
+	return string(window)
+}
+
+// Return the IUPAC codes the two overhangs have in common at each position.
+// The second return value is false if the overhangs cannot pair.
+func commonOverhang(overhang string, other string) (string, bool) {
+	if len(overhang) != len(other) {
+		return "", false
+	}
+
+	common := make([]byte, len(overhang))
+	for i := 0; i < len(overhang); i++ {
+		code, ok := commonIUPACCode(overhang[i], other[i])
+		if !ok {
+			return "", false
+		}
+		common[i] = code
+	}
+	return string(common), true
+}
+
+// Return the IUPAC code for the concrete bases represented by both codes.
+func commonIUPACCode(code byte, other byte) (byte, bool) {
+	bases, ok := IUPACBases(code)
+	otherBases, otherOk := IUPACBases(other)
+	if !ok || !otherOk {
+		return 0, false
+	}
+
+	common := ""
+	for i := 0; i < len(bases); i++ {
+		if strings.IndexByte(otherBases, bases[i]) >= 0 {
+			common += string(bases[i])
+		}
+	}
+	for iupacCode, iupacCodeBases := range iupacBases {
+		if iupacCode != 'U' && iupacCodeBases == common {
+			return iupacCode, true
+		}
+	}
+	return 0, false
+}
+
+// Predict the junction formed by ligating the end of a fragment cut by the
+// left enzyme to the start of a fragment cut by the right enzyme, e.g.
+// BamHI and BglII form GGATCT. The right fragment is flipped if its overhang
+// only pairs in reverse, as happens with non-palindromic overhangs.
+//
+// The junction is searched for the sites of both parent enzymes and of the
+// enzymes in the batch, which can be nil. Degenerate sites and degenerate
+// junction bases are matched by the concrete bases they represent.
+func LigateEnds(left *Enzyme, right *Enzyme, batch *RestrictionBatch) (LigationJunction, error) {
+	for _, enzyme := range []*Enzyme{left, right} {
+		if enzyme.OverhangType() == UnknownOverhang {
+			return LigationJunction{}, fmt.Errorf("the cut positions of %s are unknown", enzyme.Name)
+		}
+		if enzyme.HasSecondCut() {
+			return LigationJunction{}, fmt.Errorf("%s cuts on both sides of its site", enzyme.Name)
+		}
+	}
+	if left.OverhangType() != right.OverhangType() {
+		return LigationJunction{}, fmt.Errorf(
+			"%s leaves a %s end that cannot be ligated to the %s end of %s",
+			left.Name, left.OverhangType(), right.OverhangType(), right.Name)
+	}
+
+	leftContext := newCutSiteContext(left, constants.Watson)
+	leftOverhang := siteWindow(leftContext.site, leftContext.lowerCut, leftContext.higherCut)
+
+	var rightContext cutSiteContext
+	var overhang string
+	paired := false
+	for _, strand := range []constants.Strand{constants.Watson, constants.Crick} {
+		rightContext = newCutSiteContext(right, strand)
+		rightOverhang := siteWindow(rightContext.site, rightContext.lowerCut, rightContext.higherCut)
+		if overhang, paired = commonOverhang(leftOverhang, rightOverhang); paired {
+			break
+		}
+	}
+	if !paired {
+		return LigationJunction{}, fmt.Errorf("the overhangs of %s and %s cannot pair", left.Name, right.Name)
+	}
+
+	leftBases := siteWindow(leftContext.site, min(0, leftContext.lowerCut), leftContext.lowerCut)
+	rightBases := siteWindow(
+		rightContext.site,
+		rightContext.higherCut,
+		max(len(rightContext.site), rightContext.higherCut))
+
+	junction := LigationJunction{
+		Left:          left.Name,
+		Right:         right.Name,
+		Sequence:      leftBases + overhang + rightBases,
+		LigationIndex: len(leftBases),
+		OverhangType:  leftContext.overhangType,
+		Overhang:      overhang,
+	}
+	if leftContext.watsonCut > leftContext.crickCut {
+		// The watson strand of a 3' overhang is ligated after the overhang.
+		junction.LigationIndex += len(overhang)
+	}
+
+	enzymes := []Enzyme{*left, *right}
+	if batch != nil {
+		enzymes = append(enzymes, batch.Enzymes...)
+	}
+	junction.Sites = junctionSites(junction.Sequence, enzymes)
+
+	return junction, nil
+}
+
+// Predict both junctions formed by ligating fragments cut by two enzymes
+// with compatible ends, the first with the fragment cut by a on the left and
+// the second with the fragment cut by b on the left.
+func HybridJunctions(a *Enzyme, b *Enzyme, batch *RestrictionBatch) ([]LigationJunction, error) {
+	first, err := LigateEnds(a, b, batch)
+	if err != nil {
+		return nil, err
+	}
+	second, err := LigateEnds(b, a, batch)
+	if err != nil {
+		return nil, err
+	}
+	return []LigationJunction{first, second}, nil
+}
+
+// Return the sites of the enzymes in the junction sequence, sorted by
+// position. Each enzyme is checked once, even if it is listed twice.
+func junctionSites(sequence string, enzymes []Enzyme) []JunctionSite {
+	sites := []JunctionSite{}
+	checked := map[string]bool{}
+	for i := range enzymes {
+		enzyme := &enzymes[i]
+		if checked[enzyme.Name] || enzyme.Length == 0 {
+			continue
+		}
+		checked[enzyme.Name] = true
+
+		forwardSite := strings.ToUpper(enzyme.Site)
+		reverseSite := ReverseComplementSite(enzyme.Site)
+		for position := 0; position+len(forwardSite) <= len(sequence); position++ {
+			window := sequence[position : position+len(forwardSite)]
+			if matched, certain := degenerateSiteMatch(window, forwardSite); matched {
+				sites = append(sites, JunctionSite{enzyme.Name, position, constants.Watson, certain})
+			} else if reverseSite == forwardSite {
+				continue
+			} else if matched, certain := degenerateSiteMatch(window, reverseSite); matched {
+				sites = append(sites, JunctionSite{enzyme.Name, position, constants.Crick, certain})
+			}
+		}
+	}
+
+	sort.SliceStable(sites, func(i, j int) bool {
+		return sites[i].RecognitionSiteIndex < sites[j].RecognitionSiteIndex
+	})
+	return sites
+}
+
+// Match a site against a window of a sequence that can contain degenerate
+// codes. The first return value is true if the window can match the site
+// and the second is true if every base the window can have matches it.
+func degenerateSiteMatch(window string, site string) (bool, bool) {
+	certain := true
+	for i := 0; i < len(site); i++ {
+		windowBases, ok := IUPACBases(window[i])
+		siteBases, siteOk := IUPACBases(site[i])
+		if !ok || !siteOk || !strings.ContainsAny(windowBases, siteBases) {
+			return false, false
+		}
+		for j := 0; j < len(windowBases); j++ {
+			if strings.IndexByte(siteBases, windowBases[j]) < 0 {
+				certain = false
+			}
+		}
+	}
+	return true, certain
+}
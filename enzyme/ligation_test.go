@@ -0,0 +1,130 @@
+package enzyme
+
+import (
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+)
+
+func TestLigateEnds(t *testing.T) {
+	BamHI := FIXTURES["BamHI"]
+	BglII := parseNamedSite(t, "BglII", "A^GATCT")
+	DpnII := parseNamedSite(t, "DpnII", "^GATC")
+	batch := NewRestrictionBatch(DpnII, FIXTURES["EcoRI"])
+
+	junctions, err := HybridJunctions(&BamHI, &BglII, &batch)
+	if err != nil {
+		t.Fatalf("Failed to ligate BamHI and BglII ends: %s", err)
+	}
+
+	if junctions[0].Sequence != "GGATCT" || junctions[1].Sequence != "AGATCC" {
+		t.Errorf("Expected junctions GGATCT and AGATCC, got %s and %s",
+			junctions[0].Sequence, junctions[1].Sequence)
+	}
+	for _, junction := range junctions {
+		if junction.RegeneratesParentSite() || junction.MayBeRecutBy("BamHI") || junction.MayBeRecutBy("BglII") {
+			t.Errorf("Expected the %s-%s junction to destroy both sites", junction.Left, junction.Right)
+		}
+		if !junction.IsRecutBy("DpnII") || junction.MayBeRecutBy("EcoRI") {
+			t.Errorf("Expected the %s-%s junction to be re-cut by DpnII only, got %+v",
+				junction.Left, junction.Right, junction.Sites)
+		}
+		if junction.LigationIndex != 1 || junction.Overhang != "GATC" || junction.OverhangType != FivePrimeOverhang {
+			t.Errorf("Unexpected junction %+v", junction)
+		}
+	}
+
+	junction, err := LigateEnds(&BamHI, &BamHI, nil)
+	if err != nil || !junction.IsRecutBy("BamHI") || junction.Sequence != "GGATCC" {
+		t.Errorf("Expected religated BamHI ends to regenerate the site, got %+v, %v", junction, err)
+	}
+
+	SalI := parseNamedSite(t, "SalI", "G^TCGAC")
+	XhoI := parseNamedSite(t, "XhoI", "C^TCGAG")
+	junction, err = LigateEnds(&SalI, &XhoI, nil)
+	if err != nil || junction.Sequence != "GTCGAG" || junction.RegeneratesParentSite() {
+		t.Errorf("Expected a SalI-XhoI junction GTCGAG that is not re-cut, got %+v, %v", junction, err)
+	}
+}
+
+func TestLigateEndsThreePrime(t *testing.T) {
+	PstI := parseNamedSite(t, "PstI", "CTGCA^G")
+	NsiI := parseNamedSite(t, "NsiI", "ATGCA^T")
+
+	junction, err := LigateEnds(&PstI, &NsiI, nil)
+	if err != nil {
+		t.Fatalf("Failed to ligate PstI and NsiI ends: %s", err)
+	}
+	if junction.Sequence != "CTGCAT" || junction.LigationIndex != 5 || junction.OverhangType != ThreePrimeOverhang {
+		t.Errorf("Unexpected junction %+v", junction)
+	}
+	if junction.RegeneratesParentSite() {
+		t.Errorf("Expected the PstI-NsiI junction to destroy both sites")
+	}
+}
+
+func TestLigateEndsDegenerate(t *testing.T) {
+	PspPI := FIXTURES["PspPI"]
+	junction, err := LigateEnds(&PspPI, &PspPI, nil)
+	if err != nil || junction.Sequence != "GGNCC" || !junction.IsRecutBy("PspPI") {
+		t.Errorf("Expected religated PspPI ends to regenerate the site, got %+v, %v", junction, err)
+	}
+
+	// Two AvaII ends can pair as GGACC or GGTCC, so an enzyme that only
+	// cuts GGACC might re-cut the junction.
+	AvaII := parseNamedSite(t, "AvaII", "G^GWCC")
+	GACCutter := parseNamedSite(t, "GACCutter", "G^GACC")
+	batch := NewRestrictionBatch(GACCutter)
+	junction, err = LigateEnds(&AvaII, &AvaII, &batch)
+	if err != nil || junction.Sequence != "GGWCC" {
+		t.Fatalf("Expected an AvaII junction GGWCC, got %+v, %v", junction, err)
+	}
+	if junction.IsRecutBy("GACCutter") || !junction.MayBeRecutBy("GACCutter") {
+		t.Errorf("Expected GACCutter to possibly re-cut the junction, got %+v", junction.Sites)
+	}
+
+	// A GTC overhang only pairs with a GAC overhang in reverse, so the right
+	// fragment is flipped and the GGACC site is read from the crick strand.
+	GTCCutter := parseNamedSite(t, "GTCCutter", "G^GTCC")
+	junction, err = LigateEnds(&GTCCutter, &GACCutter, nil)
+	if err != nil || junction.Sequence != "GGTCC" {
+		t.Fatalf("Expected a junction GGTCC, got %+v, %v", junction, err)
+	}
+	if len(junction.Sites) != 2 || junction.Sites[1].Strand != constants.Crick || !junction.IsRecutBy("GACCutter") {
+		t.Errorf("Expected both parent sites in the junction, got %+v", junction.Sites)
+	}
+}
+
+func TestLigateEndsTypeIIS(t *testing.T) {
+	BsaI := FIXTURES["BsaI"]
+	junction, err := LigateEnds(&BsaI, &BsaI, nil)
+	if err != nil {
+		t.Fatalf("Failed to ligate BsaI ends: %s", err)
+	}
+	// The site is kept on the left fragment, but the right fragment is cut
+	// downstream of its site.
+	if junction.Sequence != "GGTCTCNNNNN" || junction.LigationIndex != 7 || junction.Overhang != "NNNN" {
+		t.Errorf("Unexpected junction %+v", junction)
+	}
+	if !junction.IsRecutBy("BsaI") {
+		t.Errorf("Expected the BsaI site to be kept on the left of the junction")
+	}
+}
+
+func TestLigateEndsIncompatible(t *testing.T) {
+	BamHI := FIXTURES["BamHI"]
+	EcoRI := FIXTURES["EcoRI"]
+	DpnI := FIXTURES["DpnI"]
+	ApaI := FIXTURES["ApaI"]
+
+	for _, right := range []Enzyme{EcoRI, DpnI, ApaI} {
+		if _, err := LigateEnds(&BamHI, &right, nil); err == nil {
+			t.Errorf("Expected BamHI and %s ends not to ligate", right.Name)
+		}
+	}
+
+	junction, err := LigateEnds(&DpnI, &DpnI, nil)
+	if err != nil || junction.Sequence != "GATC" || junction.LigationIndex != 2 || !junction.IsRecutBy("DpnI") {
+		t.Errorf("Expected blunt DpnI ends to regenerate the site, got %+v, %v", junction, err)
+	}
+}
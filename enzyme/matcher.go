@@ -0,0 +1,244 @@
+package enzyme
+
+import (
+	"math/bits"
+	"strings"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+)
+
+// The index of each concrete base in the matcher's base masks. Every other
+// byte, including degenerate bases in the searched sequence, is -1 and never
+// matches.
+var matcherBaseIndex = buildMatcherBaseIndex()
+
+func buildMatcherBaseIndex() [256]int8 {
+	var baseIndex [256]int8
+	for i := range baseIndex {
+		baseIndex[i] = -1
+	}
+	for i, base := range "ACGT" {
+		baseIndex[base] = int8(i)
+		baseIndex[base+('a'-'A')] = int8(i)
+	}
+	baseIndex['U'] = baseIndex['T']
+	baseIndex['u'] = baseIndex['T']
+	return baseIndex
+}
+
+// An enzyme and strand recognized by a matcher pattern.
+type patternHit struct {
+	enzymeIndex int
+	strand      constants.Strand
+}
+
+// A distinct IUPAC site tracked by the matcher and the enzymes that
+// recognize it.
+type matcherPattern struct {
+	site   string
+	length int
+	hits   []patternHit
+}
+
+// A bit-parallel (Shift-And) automaton that finds every recognition site of
+// many enzymes in a single pass over a sequence.
+//
+// Each distinct site occupies one bit per base in a long bit vector. After
+// reading a base the bit for position j of a site is set when the last j+1
+// bases match the first j+1 positions of the site, so a site is found when
+// its last bit is set. Degenerate IUPAC codes cost nothing extra because
+// they only set more bits in the per-base masks.
+type siteMatcher struct {
+	// The name and site of each enzyme the matcher was built for, in order.
+	enzymeNames []string
+	enzymeSites []string
+
+	words      int
+	baseMasks  [4][]uint64
+	startMask  []uint64
+	endMask    []uint64
+	endPattern []int
+	patterns   []matcherPattern
+	maxLength  int
+}
+
+// A match of a matcher pattern ending at a position in the sequence.
+type patternMatch struct {
+	start   int
+	pattern int
+}
+
+// Build a matcher for the recognition sites of the enzymes. The forward
+// site of every enzyme is tracked, along with the reverse complement for
+// non-palindromic sites. Enzymes that share a site share a pattern.
+func newSiteMatcher(enzymes []Enzyme) *siteMatcher {
+	matcher := &siteMatcher{
+		enzymeNames: make([]string, len(enzymes)),
+		enzymeSites: make([]string, len(enzymes)),
+	}
+	for i := range enzymes {
+		matcher.enzymeNames[i] = enzymes[i].Name
+		matcher.enzymeSites[i] = enzymes[i].Site
+	}
+	patternIndexBySite := map[string]int{}
+
+	addPattern := func(site string, hit patternHit) {
+		if index, ok := patternIndexBySite[site]; ok {
+			matcher.patterns[index].hits = append(matcher.patterns[index].hits, hit)
+			return
+		}
+		patternIndexBySite[site] = len(matcher.patterns)
+		matcher.patterns = append(matcher.patterns, matcherPattern{
+			site:   site,
+			length: len(site),
+			hits:   []patternHit{hit},
+		})
+	}
+
+	for i := range enzymes {
+		forwardSite := strings.ToUpper(enzymes[i].Site)
+		if forwardSite == "" {
+			continue
+		}
+		addPattern(forwardSite, patternHit{enzymeIndex: i, strand: constants.Watson})
+
+		reverseSite := ReverseComplementSite(forwardSite)
+		if reverseSite != forwardSite {
+			addPattern(reverseSite, patternHit{enzymeIndex: i, strand: constants.Crick})
+		}
+	}
+
+	totalBits := 0
+	for _, pattern := range matcher.patterns {
+		totalBits += pattern.length
+		if pattern.length > matcher.maxLength {
+			matcher.maxLength = pattern.length
+		}
+	}
+
+	matcher.words = (totalBits + 63) / 64
+	for base := range matcher.baseMasks {
+		matcher.baseMasks[base] = make([]uint64, matcher.words)
+	}
+	matcher.startMask = make([]uint64, matcher.words)
+	matcher.endMask = make([]uint64, matcher.words)
+	matcher.endPattern = make([]int, matcher.words*64)
+
+	bit := 0
+	for patternIndex, pattern := range matcher.patterns {
+		setBit(matcher.startMask, bit)
+		for i := 0; i < pattern.length; i++ {
+			for base, concreteBase := range "ACGT" {
+				if IUPACMatch(pattern.site[i], byte(concreteBase)) {
+					setBit(matcher.baseMasks[base], bit+i)
+				}
+			}
+		}
+		bit += pattern.length
+		setBit(matcher.endMask, bit-1)
+		matcher.endPattern[bit-1] = patternIndex
+	}
+
+	return matcher
+}
+
+// Return true if the matcher was built for the enzymes: the same names and
+// sites in the same order. The index of an enzyme in a pattern hit is only
+// valid for these enzymes.
+func (matcher *siteMatcher) isFor(enzymes []Enzyme) bool {
+	if len(enzymes) != len(matcher.enzymeNames) {
+		return false
+	}
+	for i := range enzymes {
+		if enzymes[i].Name != matcher.enzymeNames[i] || enzymes[i].Site != matcher.enzymeSites[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func setBit(vector []uint64, bit int) {
+	vector[bit/64] |= 1 << (bit % 64)
+}
+
+// Return a new, empty matcher state.
+func (matcher *siteMatcher) newState() []uint64 {
+	return make([]uint64, matcher.words)
+}
+
+// Advance the state by one base of the sequence.
+func (matcher *siteMatcher) step(state []uint64, base byte) {
+	baseIndex := matcherBaseIndex[base]
+	if baseIndex < 0 {
+		clear(state)
+		return
+	}
+
+	baseMask := matcher.baseMasks[baseIndex]
+	var carry uint64
+	for i, word := range state {
+		// Carry the top bit into the next word. Carrying from the last
+		// position of one pattern into the first position of the next is
+		// harmless because the start bit is always set.
+		state[i] = ((word << 1) | carry | matcher.startMask[i]) & baseMask[i]
+		carry = word >> 63
+	}
+}
+
+// Call found with the index of every pattern that ends at the last base
+// added to the state.
+func (matcher *siteMatcher) matches(state []uint64, found func(pattern int)) {
+	for i, word := range state {
+		hits := word & matcher.endMask[i]
+		for hits != 0 {
+			found(matcher.endPattern[i*64+bits.TrailingZeros64(hits)])
+			hits &= hits - 1
+		}
+	}
+}
+
+// The number of bases scanned between checks of the done channel.
+const scanCheckInterval = 1 << 16
+
+// Scan the sequence starting at the offset and call visit for every site
+// that starts at or after the offset. Sites are visited in the order they
+// end, not the order they start. If isCircular is true sites spanning the
+// origin are found as well. Scanning stops early if visit returns false or
+// done is closed. A nil done channel is never closed.
+func (matcher *siteMatcher) scan(
+	sequence string,
+	offset int,
+	isCircular bool,
+	done <-chan struct{},
+	visit func(match patternMatch, end int) bool,
+) {
+	if len(matcher.patterns) == 0 || offset < 0 || offset >= len(sequence) {
+		return
+	}
+
+	end := len(sequence)
+	if isCircular {
+		end += matcher.maxLength - 1
+	}
+
+	state := matcher.newState()
+	keepScanning := true
+	for position := offset; position < end && keepScanning; position++ {
+		if done != nil && (position-offset)%scanCheckInterval == 0 {
+			select {
+			case <-done:
+				return
+			default:
+			}
+		}
+
+		matcher.step(state, sequence[position%len(sequence)])
+		matcher.matches(state, func(pattern int) {
+			start := position - matcher.patterns[pattern].length + 1
+			if !keepScanning || start >= len(sequence) {
+				return
+			}
+			keepScanning = visit(patternMatch{start: start, pattern: pattern}, position)
+		})
+	}
+}
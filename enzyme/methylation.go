@@ -0,0 +1,490 @@
+package enzyme
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+)
+
+type MethylationType string
+
+const (
+	// Dam methylates the adenine of GATC (N6-methyladenine).
+	DamMethylation MethylationType = "dam"
+	// Dcm methylates the internal cytosine of CCWGG (5-methylcytosine).
+	DcmMethylation MethylationType = "dcm"
+	// The EcoKI Type I system of E. coli K-12 methylates AAC(N6)GTGC.
+	EcoKIMethylation MethylationType = "EcoKI"
+	// The EcoBI Type I system of E. coli B methylates TGA(N8)TGCT.
+	EcoBIMethylation MethylationType = "EcoBI"
+	// CpG methylation of the cytosine of CG, e.g. in mammalian cells or by M.SssI.
+	CpGMethylation MethylationType = "CpG"
+)
+
+// The chemical modification made by a methyltransferase, numbered as in the
+// REBASE methylation notation.
+type Modification int
+
+const (
+	N4Methylcytosine   Modification = 4
+	FiveMethylcytosine Modification = 5
+	N6Methyladenine    Modification = 6
+)
+
+// Return the base the modification is made to, A or C.
+func (modification Modification) base() byte {
+	if modification == N6Methyladenine {
+		return 'A'
+	}
+	return 'C'
+}
+
+// A methylation context is the site a methyltransferase modifies. The
+// methylated positions are given as offsets into the site on the watson strand
+// and include the base methylated on the crick strand.
+type MethylationContext struct {
+	Type               MethylationType
+	Site               string
+	MethylatedPosition []int
+	Modification       Modification
+}
+
+var MethylationContexts = map[MethylationType]MethylationContext{
+	DamMethylation:   {Type: DamMethylation, Site: "GATC", MethylatedPosition: []int{1, 2}, Modification: N6Methyladenine},
+	DcmMethylation:   {Type: DcmMethylation, Site: "CCWGG", MethylatedPosition: []int{1, 3}, Modification: FiveMethylcytosine},
+	EcoKIMethylation: {Type: EcoKIMethylation, Site: "AACNNNNNNGTGC", MethylatedPosition: []int{1, 10}, Modification: N6Methyladenine},
+	EcoBIMethylation: {Type: EcoBIMethylation, Site: "TGANNNNNNNNTGCT", MethylatedPosition: []int{2, 11}, Modification: N6Methyladenine},
+	CpGMethylation:   {Type: CpGMethylation, Site: "CG", MethylatedPosition: []int{0, 1}, Modification: FiveMethylcytosine},
+}
+
+// A host strain describes which methylation systems are active in the
+// organism the DNA was prepared from.
+type HostStrain struct {
+	Name        string
+	Methylation []MethylationType
+}
+
+var (
+	// DNA that was never methylated, e.g. a PCR product.
+	Unmethylated = HostStrain{Name: "unmethylated"}
+
+	// Common E. coli K-12 cloning strains are dam+ dcm+ and, unless the hsd
+	// locus is deleted, methylate EcoKI sites.
+	DH5Alpha = HostStrain{Name: "DH5alpha", Methylation: []MethylationType{DamMethylation, DcmMethylation, EcoKIMethylation}}
+	XL1Blue  = HostStrain{Name: "XL1-Blue", Methylation: []MethylationType{DamMethylation, DcmMethylation, EcoKIMethylation}}
+	JM109    = HostStrain{Name: "JM109", Methylation: []MethylationType{DamMethylation, DcmMethylation, EcoKIMethylation}}
+	TOP10    = HostStrain{Name: "TOP10", Methylation: []MethylationType{DamMethylation, DcmMethylation}}
+	MG1655   = HostStrain{Name: "MG1655", Methylation: []MethylationType{DamMethylation, DcmMethylation, EcoKIMethylation}}
+
+	// E. coli B strains lack dcm and BL21 carries hsdSB (rB- mB-).
+	BL21DE3 = HostStrain{Name: "BL21(DE3)", Methylation: []MethylationType{DamMethylation}}
+
+	// dam-/dcm- strains used to prepare DNA for methylation sensitive enzymes.
+	JM110          = HostStrain{Name: "JM110", Methylation: []MethylationType{EcoKIMethylation}}
+	DamDcmNegative = HostStrain{Name: "dam-/dcm-", Methylation: []MethylationType{EcoKIMethylation}}
+
+	// Mammalian genomic DNA is methylated at CpG.
+	Mammalian = HostStrain{Name: "mammalian", Methylation: []MethylationType{CpGMethylation}}
+)
+
+var HostStrains = map[string]HostStrain{
+	Unmethylated.Name:   Unmethylated,
+	DH5Alpha.Name:       DH5Alpha,
+	XL1Blue.Name:        XL1Blue,
+	JM109.Name:          JM109,
+	TOP10.Name:          TOP10,
+	MG1655.Name:         MG1655,
+	BL21DE3.Name:        BL21DE3,
+	JM110.Name:          JM110,
+	DamDcmNegative.Name: DamDcmNegative,
+	Mammalian.Name:      Mammalian,
+}
+
+// Return true if the host methylates the given context.
+func (host HostStrain) Methylates(methylationType MethylationType) bool {
+	for _, active := range host.Methylation {
+		if active == methylationType {
+			return true
+		}
+	}
+	return false
+}
+
+// A methylated base of a recognition site.
+type MethylatedBase struct {
+	// The offset of the base from the first base of the site on the watson
+	// strand. A base of the crick strand is given by the offset of the
+	// watson base it is paired with.
+	Position     int
+	Strand       constants.Strand
+	Modification Modification
+}
+
+// Matches one base of the REBASE methylation notation, e.g. 2(6) or -5(6).
+var methylationNotation = regexp.MustCompile(`^(-?\d+)\((\d)\)$`)
+
+/*
+Return the bases of the recognition site modified by the enzyme's cognate
+methyltransferase, read from the REBASE methylation line of the enzyme, e.g.
+"2(6)" for N6-methyladenine at the second base of the site.
+
+Negative positions are on the crick strand, numbered from its 5' end. REBASE
+only gives the crick strand when it differs, so the bases of a palindromic
+site listed for the watson strand are methylated on both strands. Bases at an
+unknown position, e.g. "?(5)", are left out.
+*/
+func (enzyme *Enzyme) CognateMethylation() []MethylatedBase {
+	bases := []MethylatedBase{}
+	hasCrick := false
+	for _, notation := range strings.Split(enzyme.Methylation, ",") {
+		match := methylationNotation.FindStringSubmatch(strings.TrimSpace(notation))
+		if match == nil {
+			continue
+		}
+		position, _ := strconv.Atoi(match[1])
+		modification, _ := strconv.Atoi(match[2])
+		if position == 0 || position > enzyme.Length || -position > enzyme.Length {
+			continue
+		}
+
+		base := MethylatedBase{Position: position - 1, Strand: constants.Watson, Modification: Modification(modification)}
+		if position < 0 {
+			base = MethylatedBase{Position: enzyme.Length + position, Strand: constants.Crick, Modification: Modification(modification)}
+			hasCrick = true
+		}
+		bases = append(bases, base)
+	}
+
+	if !hasCrick && enzyme.isPalindrome() {
+		for _, base := range bases {
+			bases = append(bases, MethylatedBase{
+				Position:     enzyme.Length - 1 - base.Position,
+				Strand:       constants.Crick,
+				Modification: base.Modification,
+			})
+		}
+	}
+	return bases
+}
+
+// Enzymes that only cut methylated sites and the methylation they require.
+// The REBASE methylation line does not tell these apart from enzymes blocked
+// by the same methylation, e.g. DpnI from MboI, so they are listed here.
+var MethylationDependentEnzymes = map[string]MethylationType{
+	"DpnI": DamMethylation,
+	"MalI": DamMethylation,
+}
+
+// How an enzyme responds to methylation overlapping its recognition site.
+type MethylationSensitivity string
+
+const (
+	ImpairedByMethylation MethylationSensitivity = "impaired"
+	BlockedByMethylation  MethylationSensitivity = "blocked"
+)
+
+// The sensitivity of common enzymes to methylation overlapping their
+// recognition site, summarised from the REBASE and NEB methylation
+// sensitivity tables. It is only used for enzymes without a REBASE
+// methylation line, which includes every enzyme of the database until it is
+// regenerated with the methylation data.
+var MethylationSensitivities = map[string]map[MethylationType]MethylationSensitivity{
+	// Dam
+	"AlwI":      {DamMethylation: BlockedByMethylation},
+	"BclI":      {DamMethylation: BlockedByMethylation},
+	"BsaBI":     {DamMethylation: BlockedByMethylation},
+	"BspDI":     {DamMethylation: BlockedByMethylation, CpGMethylation: BlockedByMethylation},
+	"BspEI":     {DamMethylation: BlockedByMethylation},
+	"BspHI":     {DamMethylation: BlockedByMethylation},
+	"ClaI":      {DamMethylation: BlockedByMethylation, CpGMethylation: BlockedByMethylation},
+	"DpnII":     {DamMethylation: BlockedByMethylation},
+	"HphI":      {DamMethylation: BlockedByMethylation},
+	"Hpy188I":   {DamMethylation: BlockedByMethylation},
+	"Hpy188III": {DamMethylation: BlockedByMethylation},
+	"MboI":      {DamMethylation: BlockedByMethylation},
+	"MboII":     {DamMethylation: BlockedByMethylation},
+	"NruI":      {DamMethylation: BlockedByMethylation, CpGMethylation: BlockedByMethylation},
+	"TaqI":      {DamMethylation: ImpairedByMethylation},
+	"XbaI":      {DamMethylation: BlockedByMethylation},
+
+	// Dcm
+	"Acc65I":   {DcmMethylation: BlockedByMethylation},
+	"ApaI":     {DcmMethylation: ImpairedByMethylation, CpGMethylation: BlockedByMethylation},
+	"AvaII":    {DcmMethylation: BlockedByMethylation},
+	"BanI":     {DcmMethylation: ImpairedByMethylation},
+	"BsaI":     {DcmMethylation: ImpairedByMethylation, CpGMethylation: ImpairedByMethylation},
+	"EaeI":     {DcmMethylation: BlockedByMethylation},
+	"EcoO109I": {DcmMethylation: BlockedByMethylation},
+	"EcoRII":   {DcmMethylation: BlockedByMethylation},
+	"MscI":     {DcmMethylation: BlockedByMethylation},
+	"NlaIV":    {DcmMethylation: ImpairedByMethylation},
+	"PflMI":    {DcmMethylation: BlockedByMethylation},
+	"PspGI":    {DcmMethylation: BlockedByMethylation},
+	"Sau96I":   {DcmMethylation: ImpairedByMethylation},
+	"ScrFI":    {DcmMethylation: BlockedByMethylation},
+	"SfiI":     {DcmMethylation: ImpairedByMethylation},
+	"StuI":     {DcmMethylation: BlockedByMethylation},
+
+	// CpG
+	"AatII":  {CpGMethylation: BlockedByMethylation},
+	"AscI":   {CpGMethylation: BlockedByMethylation},
+	"BssHII": {CpGMethylation: BlockedByMethylation},
+	"BstUI":  {CpGMethylation: BlockedByMethylation},
+	"EagI":   {CpGMethylation: BlockedByMethylation},
+	"HhaI":   {CpGMethylation: BlockedByMethylation},
+	"HpaII":  {CpGMethylation: BlockedByMethylation},
+	"MluI":   {CpGMethylation: BlockedByMethylation},
+	"NarI":   {CpGMethylation: BlockedByMethylation},
+	"NotI":   {CpGMethylation: BlockedByMethylation},
+	"PvuI":   {CpGMethylation: BlockedByMethylation},
+	"SacII":  {CpGMethylation: BlockedByMethylation},
+	"SalI":   {CpGMethylation: BlockedByMethylation},
+	"SmaI":   {CpGMethylation: BlockedByMethylation},
+	"XhoI":   {CpGMethylation: ImpairedByMethylation},
+}
+
+// The effect of methylation on a single recognition site.
+type SiteStatus string
+
+const (
+	Cleavable SiteStatus = "cleavable"
+	Impaired  SiteStatus = "impaired"
+	Blocked   SiteStatus = "blocked"
+)
+
+/*
+Return whether a recognition site found in the sequence can be cleaved when
+the DNA is prepared from the given host.
+
+The sensitivity of the enzyme is derived from its cognate methylation, see
+CognateMethylation. An enzyme is blocked by the modification that protects
+its site from it, so the site is blocked if the host makes the same
+modification to any of the bases the cognate methyltransferase modifies. The
+site is impaired if the host only makes that modification to other bases of
+the site.
+
+An enzyme without a REBASE methylation line falls back to
+MethylationSensitivities, and is assumed to be insensitive if it is not
+listed there either. A methylation line without a known position, e.g.
+"?(5)", is also treated as insensitive.
+
+Methylation dependent enzymes, see MethylationDependentEnzymes, only cleave
+sites methylated by the host.
+*/
+func (enzyme *Enzyme) GetMethylationStatus(
+	sequence string,
+	recognitionSiteIndex int,
+	host HostStrain,
+	isCircular bool,
+) SiteStatus {
+	if required, ok := MethylationDependentEnzymes[enzyme.Name]; ok {
+		methylated := host.Methylates(required) && len(methylatedBases(
+			sequence,
+			recognitionSiteIndex,
+			enzyme.Length,
+			MethylationContexts[required],
+			isCircular)) > 0
+		if methylated {
+			return Cleavable
+		}
+		return Blocked
+	}
+
+	if enzyme.Methylation == "" {
+		return enzyme.tabulatedMethylationStatus(sequence, recognitionSiteIndex, host, isCircular)
+	}
+
+	cognate := enzyme.CognateMethylation()
+	if len(cognate) == 0 {
+		return Cleavable
+	}
+
+	status := Cleavable
+	for _, methylationType := range host.Methylation {
+		bases := methylatedBases(
+			sequence,
+			recognitionSiteIndex,
+			enzyme.Length,
+			MethylationContexts[methylationType],
+			isCircular)
+
+		for _, base := range bases {
+			for _, cognateBase := range cognate {
+				if base == cognateBase {
+					return Blocked
+				}
+				if base.Modification == cognateBase.Modification {
+					status = Impaired
+				}
+			}
+		}
+	}
+
+	return status
+}
+
+// Return the methylation status of a site from MethylationSensitivities.
+func (enzyme *Enzyme) tabulatedMethylationStatus(
+	sequence string,
+	recognitionSiteIndex int,
+	host HostStrain,
+	isCircular bool,
+) SiteStatus {
+	status := Cleavable
+	for methylationType, sensitivity := range MethylationSensitivities[enzyme.Name] {
+		methylated := host.Methylates(methylationType) && len(methylatedBases(
+			sequence,
+			recognitionSiteIndex,
+			enzyme.Length,
+			MethylationContexts[methylationType],
+			isCircular)) > 0
+		if !methylated {
+			continue
+		}
+		if sensitivity == BlockedByMethylation {
+			return Blocked
+		}
+		status = Impaired
+	}
+	return status
+}
+
+// Return the bases of the recognition site methylated by any occurrence of
+// the context, in either orientation, that overlaps it.
+func methylatedBases(
+	sequence string,
+	siteIndex int,
+	siteLength int,
+	context MethylationContext,
+	isCircular bool,
+) []MethylatedBase {
+	bases := []MethylatedBase{}
+	if len(sequence) == 0 {
+		return bases
+	}
+
+	reverse := MethylationContext{Site: ReverseComplementSite(context.Site), Modification: context.Modification}
+	for _, position := range context.MethylatedPosition {
+		reverse.MethylatedPosition = append(reverse.MethylatedPosition, len(context.Site)-1-position)
+	}
+	orientations := []MethylationContext{context}
+	if reverse.Site != strings.ToUpper(context.Site) {
+		orientations = append(orientations, reverse)
+	}
+
+	for _, orientation := range orientations {
+		contextLength := len(orientation.Site)
+		for start := siteIndex - contextLength + 1; start < siteIndex+siteLength; start++ {
+			if !matchesAt(sequence, orientation.Site, start, isCircular) {
+				continue
+			}
+
+			for _, position := range orientation.MethylatedPosition {
+				offset := start + position - siteIndex
+				if offset < 0 || offset >= siteLength {
+					continue
+				}
+
+				// The methylated base is on the watson strand if it is the
+				// base the modification is made to, otherwise it is the
+				// base paired with it on the crick strand.
+				index := ((start+position)%len(sequence) + len(sequence)) % len(sequence)
+				strand := constants.Crick
+				if unicode.ToUpper(rune(sequence[index])) == rune(context.Modification.base()) {
+					strand = constants.Watson
+				}
+				bases = append(bases, MethylatedBase{Position: offset, Strand: strand, Modification: context.Modification})
+			}
+		}
+	}
+	return bases
+}
+
+// An interface for anything that can find recognition sites, i.e. an Enzyme
+// or a RestrictionBatch.
+type SiteFinder interface {
+	GetNextRecognitionSite(sequence string, offset int, isCircular bool) []RecognitionSiteResult
+}
+
+// MethylationFilter wraps an enzyme or batch and determines the methylation
+// status of every recognition site for DNA prepared from the host.
+//
+// A MethylationFilter can be passed to Dseq.Cut in place of the enzyme,
+// in which case blocked sites are not cut. Impaired sites are still cut,
+// but in practice the digest may be incomplete.
+type MethylationFilter struct {
+	Finder SiteFinder
+	Host   HostStrain
+}
+
+// Create a new methylation filter for the enzyme or batch.
+func NewMethylationFilter(finder SiteFinder, host HostStrain) *MethylationFilter {
+	return &MethylationFilter{
+		Finder: finder,
+		Host:   host,
+	}
+}
+
+// Get the next recognition site in the sequence after the offset that is
+// not blocked by methylation. The MethylationStatus of each result is set.
+func (filter *MethylationFilter) GetNextRecognitionSite(
+	sequence string,
+	offset int,
+	isCircular bool,
+) []RecognitionSiteResult {
+	for {
+		results := filter.Finder.GetNextRecognitionSite(sequence, offset, isCircular)
+		if results == nil {
+			return nil
+		}
+
+		cleavable := []RecognitionSiteResult{}
+		for _, result := range filter.withStatus(sequence, results, isCircular) {
+			if result.MethylationStatus != Blocked {
+				cleavable = append(cleavable, result)
+			}
+		}
+
+		if len(cleavable) > 0 {
+			return cleavable
+		}
+		offset = results[0].RecognitionSiteIndex + 1
+	}
+}
+
+// Return every recognition site in the sequence, including those blocked
+// by methylation, with its MethylationStatus set.
+func (filter *MethylationFilter) Search(sequence string, isCircular bool) ([]RecognitionSiteResult, error) {
+	sites := []RecognitionSiteResult{}
+
+	offset := 0
+	for {
+		results := filter.Finder.GetNextRecognitionSite(sequence, offset, isCircular)
+		if results == nil {
+			break
+		}
+
+		sites = append(sites, filter.withStatus(sequence, results, isCircular)...)
+		offset = results[0].RecognitionSiteIndex + 1
+	}
+
+	return sites, nil
+}
+
+func (filter *MethylationFilter) withStatus(
+	sequence string,
+	results []RecognitionSiteResult,
+	isCircular bool,
+) []RecognitionSiteResult {
+	for i := range results {
+		results[i].MethylationStatus = results[i].Enzyme.GetMethylationStatus(
+			sequence,
+			results[i].RecognitionSiteIndex,
+			filter.Host,
+			isCircular)
+	}
+	return results
+}
@@ -0,0 +1,144 @@
+package enzyme
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+)
+
+// Return the enzyme with the methylation line it has in REBASE.
+func withMethylation(t *testing.T, name string, notation string, methylation string) Enzyme {
+	t.Helper()
+	enzyme := parseNamedSite(t, name, notation)
+	enzyme.Methylation = methylation
+	return enzyme
+}
+
+func TestCognateMethylation(t *testing.T) {
+	tests := []struct {
+		enzyme   Enzyme
+		expected []MethylatedBase
+	}{
+		// A palindrome lists only the watson strand.
+		{withMethylation(t, "AccIII", "T^CCGGA", "6(6)"), []MethylatedBase{
+			{Position: 5, Strand: constants.Watson, Modification: N6Methyladenine},
+			{Position: 0, Strand: constants.Crick, Modification: N6Methyladenine},
+		}},
+		// The crick strand is numbered from its own 5' end.
+		{withMethylation(t, "AciI", "C^CGC", "1(5),-2(5)"), []MethylatedBase{
+			{Position: 0, Strand: constants.Watson, Modification: FiveMethylcytosine},
+			{Position: 2, Strand: constants.Crick, Modification: FiveMethylcytosine},
+		}},
+		{withMethylation(t, "AccII", "CG^CG", "1(5)"), []MethylatedBase{
+			{Position: 0, Strand: constants.Watson, Modification: FiveMethylcytosine},
+			{Position: 3, Strand: constants.Crick, Modification: FiveMethylcytosine},
+		}},
+		// An unknown position is left out.
+		{withMethylation(t, "Unknown", "GGCC", "?(5)"), []MethylatedBase{}},
+		{FIXTURES["EcoRI"], []MethylatedBase{}},
+	}
+
+	for _, test := range tests {
+		if actual := test.enzyme.CognateMethylation(); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Expected %s to have cognate methylation %v, got %v", test.enzyme.Name, test.expected, actual)
+		}
+	}
+}
+
+func TestAccIIIBlockedByOverlappingDam(t *testing.T) {
+	AccIII := withMethylation(t, "AccIII", "T^CCGGA", "6(6)")
+
+	// TCCGGATC overlaps a GATC Dam site at the methylated A, GATCCGGA
+	// overlaps one at the A on the crick strand and TCCGGAA does not.
+	sequence := "AAAATCCGGATCAAAGATCCGGAAAATCCGGAAAAA"
+
+	if status := AccIII.GetMethylationStatus(sequence, 4, DH5Alpha, false); status != Blocked {
+		t.Errorf("Expected AccIII site overlapping dam to be blocked in DH5alpha, got %s", status)
+	}
+	if status := AccIII.GetMethylationStatus(sequence, 17, DH5Alpha, false); status != Blocked {
+		t.Errorf("Expected AccIII site overlapping dam on the crick strand to be blocked, got %s", status)
+	}
+	if status := AccIII.GetMethylationStatus(sequence, 26, DH5Alpha, false); status != Cleavable {
+		t.Errorf("Expected AccIII site without dam to be cleavable, got %s", status)
+	}
+	if status := AccIII.GetMethylationStatus(sequence, 4, JM110, false); status != Cleavable {
+		t.Errorf("Expected AccIII site to be cleavable in a dam- strain, got %s", status)
+	}
+}
+
+func TestImpairedByMethylationOfAnotherBase(t *testing.T) {
+	// Not a REBASE methylation line. The cognate methyltransferase modifies
+	// the first A of the site while Dam modifies the second.
+	ez := withMethylation(t, "Impaired", "A^GATCT", "1(6)")
+
+	if status := ez.GetMethylationStatus("AAAGATCTAA", 2, DH5Alpha, false); status != Impaired {
+		t.Errorf("Expected site methylated at another base to be impaired, got %s", status)
+	}
+}
+
+func TestAciIBlockedByCpGMethylation(t *testing.T) {
+	AciI := withMethylation(t, "AciI", "C^CGC", "1(5),-2(5)")
+	sequence := "AAACCGCAAA"
+
+	if status := AciI.GetMethylationStatus(sequence, 3, Mammalian, false); status != Blocked {
+		t.Errorf("Expected AciI to be blocked by CpG methylation, got %s", status)
+	}
+	if status := AciI.GetMethylationStatus(sequence, 3, DH5Alpha, false); status != Cleavable {
+		t.Errorf("Expected AciI to be cleavable without CpG methylation, got %s", status)
+	}
+}
+
+func TestDcmMethylatesReverseOrientation(t *testing.T) {
+	// An enzyme sensitive to the 5-methylcytosine of CCWGG on either strand.
+	ez := withMethylation(t, "Dcm", "C^CWGG", "2(5)")
+	sequence := "AAACCAGGAAACCTGGAAA"
+
+	for _, index := range []int{3, 11} {
+		if status := ez.GetMethylationStatus(sequence, index, DH5Alpha, false); status != Blocked {
+			t.Errorf("Expected the site at %d to be blocked by dcm, got %s", index, status)
+		}
+	}
+}
+
+func TestDpnIRequiresMethylation(t *testing.T) {
+	DpnI := FIXTURES["DpnI"]
+	sequence := "AAAAGATCAAAA"
+
+	if status := DpnI.GetMethylationStatus(sequence, 4, DH5Alpha, false); status != Cleavable {
+		t.Errorf("Expected DpnI to cut dam methylated DNA, got %s", status)
+	}
+	if status := DpnI.GetMethylationStatus(sequence, 4, Unmethylated, false); status != Blocked {
+		t.Errorf("Expected DpnI not to cut unmethylated DNA, got %s", status)
+	}
+}
+
+func TestMethylationFilter(t *testing.T) {
+	batch := NewRestrictionBatch(
+		withMethylation(t, "AccIII", "T^CCGGA", "6(6)"),
+		FIXTURES["EcoRI"],
+	)
+	sequence := "AAAATCCGGATCAAAAAAGAATTCAAAAAA"
+
+	filter := NewMethylationFilter(&batch, DH5Alpha)
+
+	sites, err := filter.Search(sequence, false)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+	if len(sites) != 2 {
+		t.Fatalf("Expected 2 sites, got %d", len(sites))
+	}
+	if sites[0].Enzyme.Name != "AccIII" || sites[0].MethylationStatus != Blocked {
+		t.Errorf("Expected AccIII site to be blocked, got %s %s", sites[0].Enzyme.Name, sites[0].MethylationStatus)
+	}
+	if sites[1].Enzyme.Name != "EcoRI" || sites[1].MethylationStatus != Cleavable {
+		t.Errorf("Expected EcoRI site to be cleavable, got %s %s", sites[1].Enzyme.Name, sites[1].MethylationStatus)
+	}
+
+	// The blocked AccIII site is skipped
+	results := filter.GetNextRecognitionSite(sequence, 0, false)
+	if len(results) != 1 || results[0].Enzyme.Name != "EcoRI" {
+		t.Errorf("Expected the next cleavable site to be EcoRI, got %v", results)
+	}
+}
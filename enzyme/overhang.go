@@ -0,0 +1,89 @@
+package enzyme
+
+import (
+	"strings"
+)
+
+type EnzymeOverhangType string
+
+const (
+	FivePrimeOverhang  EnzymeOverhangType = "5'"
+	ThreePrimeOverhang EnzymeOverhangType = "3'"
+	BluntOverhang      EnzymeOverhangType = "blunt"
+	UnknownOverhang    EnzymeOverhangType = "unknown"
+)
+
+// Calculate the overhang left by an enzyme from its recognition site and the
+// REBASE 5' and 3' cut positions.
+//
+// The overhang length is signed to mirror the Overhang of a Dseq and
+// Biopython's ovhg: a negative length is a 5' overhang, a positive length is
+// a 3' overhang and zero is a blunt end. The overhang sequence is read from the
+// watson strand and uses N for bases that lie outside the recognition site,
+// e.g. GGTCTC(1/5) -> -4, NNNN and G^AATTC -> -4, AATT.
+func CalculateOverhang(site string, fivePrimeCutSite int, threePrimeCutSite int) (int, string) {
+	fivePrimeOffset := rebaseCutOffset(fivePrimeCutSite)
+	threePrimeOffset := rebaseCutOffset(threePrimeCutSite)
+
+	start, end := fivePrimeOffset, threePrimeOffset
+	if start > end {
+		start, end = end, start
+	}
+
+	overhangSequence := make([]byte, 0, end-start)
+	for i := start; i < end; i++ {
+		if i >= 0 && i < len(site) {
+			overhangSequence = append(overhangSequence, upperBase(site[i]))
+		} else {
+			overhangSequence = append(overhangSequence, 'N')
+		}
+	}
+
+	return fivePrimeOffset - threePrimeOffset, string(overhangSequence)
+}
+
+// Return the type of end the enzyme leaves after cutting.
+func (enzyme *Enzyme) OverhangType() EnzymeOverhangType {
+	if enzyme.NumberOfCuts == UnknownCuts {
+		return UnknownOverhang
+	}
+
+	overhangLength, _ := CalculateOverhang(enzyme.Site, enzyme.FivePrimeCutSite, enzyme.ThreePrimeCutSite)
+	switch {
+	case overhangLength < 0:
+		return FivePrimeOverhang
+	case overhangLength > 0:
+		return ThreePrimeOverhang
+	default:
+		return BluntOverhang
+	}
+}
+
+// Return the concrete overhang bases between the watson and crick cut
+// positions in the sequence, read from the watson strand. If the sequence is
+// circular the positions wrap around the origin, otherwise an empty string is
+// returned when the overhang extends beyond the ends of the sequence.
+func overhangBases(sequence string, watsonCutIndex int, crickCutIndex int, isCircular bool) string {
+	start, end := watsonCutIndex, crickCutIndex
+	if start > end {
+		start, end = end, start
+	}
+
+	if len(sequence) == 0 {
+		return ""
+	}
+
+	if !isCircular {
+		if start < 0 || end > len(sequence) {
+			return ""
+		}
+		return strings.ToUpper(sequence[start:end])
+	}
+
+	overhang := make([]byte, 0, end-start)
+	for i := start; i < end; i++ {
+		index := ((i % len(sequence)) + len(sequence)) % len(sequence)
+		overhang = append(overhang, upperBase(sequence[index]))
+	}
+	return string(overhang)
+}
@@ -0,0 +1,77 @@
+package enzyme
+
+import (
+	"testing"
+)
+
+func TestCalculateOverhang(t *testing.T) {
+	cases := []struct {
+		site              string
+		fivePrimeCutSite  int
+		threePrimeCutSite int
+		length            int
+		sequence          string
+	}{
+		{"GAATTC", 1, 5, -4, "AATT"},
+		{"CTGCAG", 5, 1, 4, "TGCA"},
+		{"CCCGGG", 3, 3, 0, ""},
+		{"GGTCTC", 7, 11, -4, "NNNN"},
+		{"GACNNNNNNGTC", 7, 5, 2, "NN"},
+		{"ACNNNNGTAYC", -11, -16, 5, "NNNNN"},
+		{"ccwwgg", 1, 5, -4, "CWWG"},
+	}
+
+	for _, c := range cases {
+		length, sequence := CalculateOverhang(c.site, c.fivePrimeCutSite, c.threePrimeCutSite)
+		if length != c.length || sequence != c.sequence {
+			t.Errorf("Expected %d, %s for %s(%d/%d), got %d, %s",
+				c.length, c.sequence, c.site, c.fivePrimeCutSite, c.threePrimeCutSite, length, sequence)
+		}
+	}
+}
+
+func TestOverhangType(t *testing.T) {
+	EcoRI := FIXTURES["EcoRI"]
+	if EcoRI.OverhangType() != FivePrimeOverhang {
+		t.Errorf("Expected EcoRI to leave a 5' overhang, got %s", EcoRI.OverhangType())
+	}
+
+	BaeI := FIXTURES["BaeI"]
+	if BaeI.OverhangType() != ThreePrimeOverhang {
+		t.Errorf("Expected BaeI to leave a 3' overhang, got %s", BaeI.OverhangType())
+	}
+
+	unknown := Enzyme{Name: "Unknown", Site: "GATC", Length: 4, NumberOfCuts: UnknownCuts}
+	if unknown.OverhangType() != UnknownOverhang {
+		t.Errorf("Expected an unknown overhang, got %s", unknown.OverhangType())
+	}
+}
+
+func TestRecognitionSiteResultOverhang(t *testing.T) {
+	enzyme := FIXTURES["BsaI"]
+
+	// The BsaI overhang lies outside the recognition site and differs
+	// at every site.
+	sequence := "NNGGTCTCNCACANNNNCTCTNGAGACCNN"
+	results := enzyme.GetNextRecognitionSite(sequence, 0, false)
+	if results[0].Overhang != "CACA" {
+		t.Errorf("Expected overhang CACA, got %s", results[0].Overhang)
+	}
+
+	results = enzyme.GetNextRecognitionSite(sequence, 3, false)
+	if results[0].Overhang != "CTCT" {
+		t.Errorf("Expected overhang CTCT, got %s", results[0].Overhang)
+	}
+
+	// The overhang wraps around the origin of a circular sequence.
+	circular := "CGTNNNNNNNNNNNNGGTCTCNA"
+	results = enzyme.GetNextRecognitionSite(circular, 0, true)
+	if results[0].Overhang != "ACGT" {
+		t.Errorf("Expected overhang ACGT, got %s", results[0].Overhang)
+	}
+
+	results = enzyme.GetNextRecognitionSite(circular, 0, false)
+	if results[0].Overhang != "" {
+		t.Errorf("Expected no overhang on a linear sequence, got %s", results[0].Overhang)
+	}
+}
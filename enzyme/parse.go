@@ -0,0 +1,134 @@
+package enzyme
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matches REBASE site notation, an IUPAC site with optional cut positions
+// before and after it in parentheses, e.g. (8/13)GACNNNNNNTCA(12/7).
+var siteNotationPattern = regexp.MustCompile(
+	`^(?:\((-?\d+)/(-?\d+)\))?([A-Za-z^]+)(?:\((-?\d+)/(-?\d+)\))?$`)
+
+// Parse a recognition site written in REBASE notation into an enzyme. The
+// enzyme is named after the notation; set Name to give it a better one.
+//
+// Three forms are accepted:
+//
+//	G^AATTC                  - the caret marks the cut on the watson strand;
+//	                           the crick strand is cut at the mirrored position
+//	GGTCTC(1/5)              - cuts 1 and 5 bases after the site on the watson
+//	                           and crick strands
+//	(8/13)GACNNNNNNTCA(12/7) - cuts on both sides of the site (Type IIB)
+//
+// A site without a caret or parentheses has unknown cut positions. The cut
+// positions are stored with REBASE's numbering, see script/rebase.go.
+func ParseSite(notation string) (Enzyme, error) {
+	matches := siteNotationPattern.FindStringSubmatch(strings.TrimSpace(notation))
+	if matches == nil {
+		return Enzyme{}, fmt.Errorf("invalid site notation: %s", notation)
+	}
+
+	site := strings.ToUpper(matches[3])
+	caretIndex := strings.IndexByte(site, '^')
+	if caretIndex >= 0 {
+		if strings.Count(site, "^") > 1 {
+			return Enzyme{}, fmt.Errorf("site notation %s has more than one cut mark", notation)
+		}
+		if matches[1] != "" || matches[4] != "" {
+			return Enzyme{}, fmt.Errorf("site notation %s mixes a cut mark with cut positions", notation)
+		}
+		site = strings.Replace(site, "^", "", 1)
+	}
+
+	regexpFor, regexpRev, err := CompileSiteRegexps(site)
+	if err != nil {
+		return Enzyme{}, err
+	}
+
+	enzyme := Enzyme{
+		Name:         notation,
+		Site:         site,
+		Length:       len(site),
+		Substrate:    "DNA",
+		RegexpFor:    regexpFor,
+		RegexpRev:    regexpRev,
+		NumberOfCuts: UnknownCuts,
+	}
+	if enzyme.Length == 0 {
+		return Enzyme{}, fmt.Errorf("site notation %s has no recognition site", notation)
+	}
+
+	// The cut offsets, from the first base of the site, of the first and
+	// second pair of cuts.
+	var cutOffsets []int
+
+	switch {
+	case caretIndex >= 0:
+		cutOffsets = []int{caretIndex, enzyme.Length - caretIndex}
+
+	case matches[1] != "" || matches[4] != "":
+		if matches[1] != "" {
+			before, err := convertCutPositions(matches[1], matches[2])
+			if err != nil {
+				return Enzyme{}, err
+			}
+			cutOffsets = append(cutOffsets, -before[0], -before[1])
+		}
+		if matches[4] != "" {
+			after, err := convertCutPositions(matches[4], matches[5])
+			if err != nil {
+				return Enzyme{}, err
+			}
+			cutOffsets = append(cutOffsets, enzyme.Length+after[0], enzyme.Length+after[1])
+		}
+	}
+
+	if len(cutOffsets) == 0 {
+		return enzyme, nil
+	}
+
+	enzyme.FivePrimeCutSite = offsetToRebaseCut(cutOffsets[0])
+	enzyme.ThreePrimeCutSite = offsetToRebaseCut(cutOffsets[1])
+	enzyme.NumberOfCuts = TwoCuts
+	if len(cutOffsets) == 4 {
+		enzyme.FivePrimeCutSite2 = offsetToRebaseCut(cutOffsets[2])
+		enzyme.ThreePrimeCutSite2 = offsetToRebaseCut(cutOffsets[3])
+		enzyme.NumberOfCuts = FourCuts
+	}
+
+	enzyme.CutType = StickyEnd
+	if cutOffsets[0] == cutOffsets[1] {
+		enzyme.CutType = BluntEnd
+	}
+
+	enzyme.OverhangLength, enzyme.OverhangSequence = CalculateOverhang(
+		enzyme.Site,
+		enzyme.FivePrimeCutSite,
+		enzyme.ThreePrimeCutSite)
+
+	return enzyme, nil
+}
+
+func convertCutPositions(watson string, crick string) ([]int, error) {
+	watsonCut, err := strconv.Atoi(watson)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cut position: %s", watson)
+	}
+	crickCut, err := strconv.Atoi(crick)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cut position: %s", crick)
+	}
+	return []int{watsonCut, crickCut}, nil
+}
+
+// Convert an offset from the first base of the recognition site into a
+// REBASE cut position. This is the inverse of rebaseCutOffset.
+func offsetToRebaseCut(offset int) int {
+	if offset <= 0 {
+		return offset - 1
+	}
+	return offset
+}
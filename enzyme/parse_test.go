@@ -0,0 +1,82 @@
+package enzyme
+
+import (
+	"testing"
+)
+
+func TestParseSite(t *testing.T) {
+	tests := []struct {
+		notation string
+		fixture  string
+	}{
+		{"G^AATTC", "EcoRI"},
+		{"GGGCC^C", "ApaI"},
+		{"GA^TC", "DpnI"},
+		{"GGTCTC(1/5)", "BsaI"},
+		{"RGATCY(-5/-1)", "MflI"},
+		{"(10/15)ACNNNNGTAYC(12/7)", "BaeI"},
+	}
+
+	for _, test := range tests {
+		enzyme, err := ParseSite(test.notation)
+		if err != nil {
+			t.Errorf("Error parsing %s: %v", test.notation, err)
+			continue
+		}
+
+		expected := FIXTURES[test.fixture]
+		if enzyme.Site != expected.Site || enzyme.Length != expected.Length {
+			t.Errorf("Expected site %s for %s, got %s", expected.Site, test.notation, enzyme.Site)
+		}
+		if enzyme.FivePrimeCutSite != expected.FivePrimeCutSite ||
+			enzyme.ThreePrimeCutSite != expected.ThreePrimeCutSite ||
+			enzyme.FivePrimeCutSite2 != expected.FivePrimeCutSite2 ||
+			enzyme.ThreePrimeCutSite2 != expected.ThreePrimeCutSite2 {
+			t.Errorf("Expected cuts %d %d %d %d for %s, got %d %d %d %d", expected.FivePrimeCutSite, expected.ThreePrimeCutSite,
+				expected.FivePrimeCutSite2, expected.ThreePrimeCutSite2, test.notation, enzyme.FivePrimeCutSite,
+				enzyme.ThreePrimeCutSite, enzyme.FivePrimeCutSite2, enzyme.ThreePrimeCutSite2)
+		}
+		if enzyme.NumberOfCuts != expected.NumberOfCuts || enzyme.CutType != expected.CutType {
+			t.Errorf("Expected %d %s cuts for %s, got %d %s", expected.NumberOfCuts, expected.CutType,
+				test.notation, enzyme.NumberOfCuts, enzyme.CutType)
+		}
+		if enzyme.OverhangLength != expected.OverhangLength || enzyme.OverhangSequence != expected.OverhangSequence {
+			t.Errorf("Expected overhang %d %s for %s, got %d %s", expected.OverhangLength, expected.OverhangSequence,
+				test.notation, enzyme.OverhangLength, enzyme.OverhangSequence)
+		}
+		if enzyme.RegexpFor.String() != expected.RegexpFor.String() || enzyme.RegexpRev.String() != expected.RegexpRev.String() {
+			t.Errorf("Expected regexps %s %s for %s, got %s %s", expected.RegexpFor, expected.RegexpRev,
+				test.notation, enzyme.RegexpFor, enzyme.RegexpRev)
+		}
+	}
+}
+
+func TestParseSiteCuts(t *testing.T) {
+	enzyme, err := ParseSite("GGTCTC(1/5)")
+	if err != nil {
+		t.Fatalf("Error parsing: %v", err)
+	}
+
+	sites, err := enzyme.Search("AAGGTCTCAAAAAAA", false)
+	if err != nil || len(sites) != 1 || sites[0] != 9 {
+		t.Errorf("Expected a cut at 9, got %v %v", sites, err)
+	}
+}
+
+func TestParseSiteUnknownCuts(t *testing.T) {
+	enzyme, err := ParseSite("gaattc")
+	if err != nil {
+		t.Fatalf("Error parsing: %v", err)
+	}
+	if enzyme.Site != "GAATTC" || enzyme.NumberOfCuts != UnknownCuts {
+		t.Errorf("Expected GAATTC with unknown cuts, got %s %d", enzyme.Site, enzyme.NumberOfCuts)
+	}
+}
+
+func TestParseSiteInvalid(t *testing.T) {
+	for _, notation := range []string{"", "G^AA^TTC", "G^AATTC(1/5)", "GAXTTC", "GAATTC(1/)", "(1/5)"} {
+		if _, err := ParseSite(notation); err == nil {
+			t.Errorf("Expected an error parsing %q", notation)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package enzyme
+
+import (
+	"sort"
+	"strings"
+)
+
+// A recognition site and its cut offsets read in one orientation.
+type siteOrientation struct {
+	site         string
+	fivePrime    int
+	threePrime   int
+	fivePrime2   int
+	threePrime2  int
+	hasSecondCut bool
+}
+
+// Return the enzyme's site and cut offsets as read on the watson strand and
+// as read on the crick strand.
+func (enzyme *Enzyme) orientations() []siteOrientation {
+	fivePrime := rebaseCutOffset(enzyme.FivePrimeCutSite)
+	threePrime := rebaseCutOffset(enzyme.ThreePrimeCutSite)
+	fivePrime2 := rebaseCutOffset(enzyme.FivePrimeCutSite2)
+	threePrime2 := rebaseCutOffset(enzyme.ThreePrimeCutSite2)
+
+	forward := siteOrientation{
+		site:         strings.ToUpper(enzyme.Site),
+		fivePrime:    fivePrime,
+		threePrime:   threePrime,
+		fivePrime2:   fivePrime2,
+		threePrime2:  threePrime2,
+		hasSecondCut: enzyme.HasSecondCut(),
+	}
+
+	reverse := siteOrientation{
+		site:         ReverseComplementSite(enzyme.Site),
+		fivePrime:    enzyme.Length - threePrime,
+		threePrime:   enzyme.Length - fivePrime,
+		hasSecondCut: enzyme.HasSecondCut(),
+	}
+	if enzyme.HasSecondCut() {
+		// The second cut on the watson strand becomes the first cut
+		// when the site is read from the crick strand.
+		reverse.fivePrime, reverse.fivePrime2 = enzyme.Length-threePrime2, enzyme.Length-threePrime
+		reverse.threePrime, reverse.threePrime2 = enzyme.Length-fivePrime2, enzyme.Length-fivePrime
+	}
+
+	return []siteOrientation{forward, reverse}
+}
+
+// Return true if both enzymes recognize the same site, in either orientation.
+func (enzyme *Enzyme) HasSameSite(other *Enzyme) bool {
+	for _, orientation := range other.orientations() {
+		if strings.ToUpper(enzyme.Site) == orientation.site {
+			return true
+		}
+	}
+	return false
+}
+
+// Return true if both enzymes recognize the same site and cut it at the same
+// positions. Enzymes where REBASE does not know the cut positions are never
+// isoschizomers of each other.
+func (enzyme *Enzyme) IsIsoschizomer(other *Enzyme) bool {
+	if enzyme.NumberOfCuts == UnknownCuts || other.NumberOfCuts == UnknownCuts {
+		return false
+	}
+
+	forward := enzyme.orientations()[0]
+	for _, orientation := range other.orientations() {
+		if forward == orientation {
+			return true
+		}
+	}
+	return false
+}
+
+// Return true if both enzymes recognize the same site but cut it at
+// different positions, e.g. SmaI (CCC^GGG) and XmaI (C^CCGGG).
+func (enzyme *Enzyme) IsNeoschizomer(other *Enzyme) bool {
+	if enzyme.NumberOfCuts == UnknownCuts || other.NumberOfCuts == UnknownCuts {
+		return false
+	}
+	return enzyme.HasSameSite(other) && !enzyme.IsIsoschizomer(other)
+}
+
+// Return the isoschizomers of the enzyme from the candidates, sorted by name.
+// The enzyme itself is not included.
+func Isoschizomers(enzyme Enzyme, candidates []Enzyme) []Enzyme {
+	return filterSchizomers(enzyme, candidates, enzyme.IsIsoschizomer)
+}
+
+// Return the neoschizomers of the enzyme from the candidates, sorted by name.
+func Neoschizomers(enzyme Enzyme, candidates []Enzyme) []Enzyme {
+	return filterSchizomers(enzyme, candidates, enzyme.IsNeoschizomer)
+}
+
+func filterSchizomers(enzyme Enzyme, candidates []Enzyme, include func(*Enzyme) bool) []Enzyme {
+	matches := []Enzyme{}
+	for i := range candidates {
+		if candidates[i].Name == enzyme.Name {
+			continue
+		}
+		if include(&candidates[i]) {
+			matches = append(matches, candidates[i])
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Name < matches[j].Name
+	})
+	return matches
+}
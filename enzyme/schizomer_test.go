@@ -0,0 +1,63 @@
+package enzyme
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestIsIsoschizomer(t *testing.T) {
+	BsaI := FIXTURES["BsaI"]
+
+	Eco31I := FIXTURES["BsaI"]
+	Eco31I.Name = "Eco31I"
+
+	if !BsaI.IsIsoschizomer(&Eco31I) {
+		t.Errorf("Expected Eco31I to be an isoschizomer of BsaI")
+	}
+
+	// The same enzyme described from the crick strand, GAGACC(-5/-1)
+	reversed := Enzyme{
+		Name:              "BsaIRev",
+		Site:              "GAGACC",
+		Length:            6,
+		RegexpFor:         regexp.MustCompile("(?i)GAGACC"),
+		RegexpRev:         regexp.MustCompile("(?i)GGTCTC"),
+		NumberOfCuts:      TwoCuts,
+		FivePrimeCutSite:  -6,
+		ThreePrimeCutSite: -2,
+	}
+	if !BsaI.IsIsoschizomer(&reversed) || !reversed.IsIsoschizomer(&BsaI) {
+		t.Errorf("Expected a site described on the crick strand to be an isoschizomer")
+	}
+
+	EcoRI := FIXTURES["EcoRI"]
+	if BsaI.IsIsoschizomer(&EcoRI) || BsaI.HasSameSite(&EcoRI) {
+		t.Errorf("Expected EcoRI not to share a site with BsaI")
+	}
+}
+
+func TestIsNeoschizomer(t *testing.T) {
+	SmaI := Enzyme{Name: "SmaI", Site: "CCCGGG", Length: 6, NumberOfCuts: TwoCuts, FivePrimeCutSite: 3, ThreePrimeCutSite: 3}
+	XmaI := Enzyme{Name: "XmaI", Site: "CCCGGG", Length: 6, NumberOfCuts: TwoCuts, FivePrimeCutSite: 1, ThreePrimeCutSite: 5}
+	TspMI := Enzyme{Name: "TspMI", Site: "CCCGGG", Length: 6, NumberOfCuts: TwoCuts, FivePrimeCutSite: 1, ThreePrimeCutSite: 5}
+	unknown := Enzyme{Name: "Unknown", Site: "CCCGGG", Length: 6, NumberOfCuts: UnknownCuts}
+
+	if !SmaI.IsNeoschizomer(&XmaI) {
+		t.Errorf("Expected XmaI to be a neoschizomer of SmaI")
+	}
+	if SmaI.IsNeoschizomer(&unknown) || SmaI.IsIsoschizomer(&unknown) {
+		t.Errorf("Expected an enzyme with unknown cuts to be neither an iso- nor neoschizomer")
+	}
+
+	candidates := []Enzyme{TspMI, SmaI, XmaI, unknown, FIXTURES["EcoRI"]}
+
+	neoschizomers := Neoschizomers(SmaI, candidates)
+	if len(neoschizomers) != 2 || neoschizomers[0].Name != "TspMI" || neoschizomers[1].Name != "XmaI" {
+		t.Errorf("Expected neoschizomers [TspMI XmaI], got %v", neoschizomers)
+	}
+
+	isoschizomers := Isoschizomers(XmaI, candidates)
+	if len(isoschizomers) != 1 || isoschizomers[0].Name != "TspMI" {
+		t.Errorf("Expected isoschizomers [TspMI], got %v", isoschizomers)
+	}
+}
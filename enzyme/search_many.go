@@ -0,0 +1,78 @@
+package enzyme
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// A named sequence to search, e.g. one plasmid of a QC run.
+type SequenceInput struct {
+	Name       string
+	Sequence   string
+	IsCircular bool
+}
+
+// The recognition sites found in one input sequence.
+type SequenceSites struct {
+	Name  string
+	Sites []RecognitionSiteResult
+}
+
+// Search many sequences for the sites of every enzyme in the batch using a
+// pool of at most workers goroutines. If workers is zero or less one worker
+// is used per CPU.
+//
+// The results are returned in the same order as the inputs. If the context
+// is cancelled or its deadline passes before every sequence is searched the
+// context's error is returned along with no results. Searches already
+// running stop reading their sequence shortly after the context ends.
+func (restrictionBatch *RestrictionBatch) SearchMany(
+	ctx context.Context,
+	inputs []SequenceInput,
+	workers int,
+) ([]SequenceSites, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	results := make([]SequenceSites, len(inputs))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				input := inputs[index]
+				sites, err := restrictionBatch.findAll(ctx, input.Sequence, input.IsCircular)
+				if err != nil {
+					continue
+				}
+				results[index] = SequenceSites{Name: input.Name, Sites: sites}
+			}
+		}()
+	}
+
+dispatch:
+	for index := range inputs {
+		select {
+		case indexes <- index:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+
+	// A search may have been stopped by the context after the last index
+	// was sent, so check the context once the workers have stopped.
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
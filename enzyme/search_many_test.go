@@ -0,0 +1,66 @@
+package enzyme
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestSearchMany(t *testing.T) {
+	batch := NewRestrictionBatch(
+		FIXTURES["EcoRI"],
+		FIXTURES["BsaI"],
+	)
+
+	inputs := []SequenceInput{}
+	for i := 0; i < 50; i++ {
+		inputs = append(inputs, SequenceInput{
+			Name:     fmt.Sprintf("plasmid%d", i),
+			Sequence: fmt.Sprintf("%sGAATTC%s", randomSequence(int64(i), i*10), randomSequence(int64(i+100), 20)),
+		})
+	}
+
+	results, err := batch.SearchMany(context.Background(), inputs, 4)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+
+	if len(results) != len(inputs) {
+		t.Fatalf("Expected %d results, got %d", len(inputs), len(results))
+	}
+	for i, result := range results {
+		if result.Name != inputs[i].Name {
+			t.Errorf("Expected result %d to be %s, got %s", i, inputs[i].Name, result.Name)
+		}
+
+		expected := batch.FindAll(inputs[i].Sequence, false)
+		if len(result.Sites) != len(expected) {
+			t.Errorf("Expected %d sites in %s, got %d", len(expected), result.Name, len(result.Sites))
+		}
+	}
+}
+
+func TestSearchManyCancelled(t *testing.T) {
+	batch := NewRestrictionBatch(FIXTURES["EcoRI"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	inputs := []SequenceInput{{Name: "plasmid", Sequence: "GAATTC"}}
+	results, err := batch.SearchMany(ctx, inputs, 1)
+	if err != context.Canceled || results != nil {
+		t.Errorf("Expected a cancelled search, got %v and %v", results, err)
+	}
+}
+
+func TestFindAllStopsWhenCancelled(t *testing.T) {
+	batch := NewRestrictionBatch(FIXTURES["EcoRI"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := batch.findAll(ctx, benchmarkGenome(), true)
+	if err != context.Canceled || results != nil {
+		t.Errorf("Expected a cancelled search, got %d results and %v", len(results), err)
+	}
+}
@@ -0,0 +1,191 @@
+package enzyme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+)
+
+// Get the next site in the sequence after the offset that is within
+// maxMismatches substitutions of the enzyme's recognition site. This relaxed
+// search is used to explain unexpected bands caused by star activity.
+//
+// The Mismatches of each result hold the offsets into the recognition site,
+// as written on the strand the site was found on, of the substituted bases.
+// Exact sites are returned with no mismatches.
+func (enzyme *Enzyme) GetNextRecognitionSiteWithMismatches(
+	sequence string,
+	offset int,
+	isCircular bool,
+	maxMismatches int,
+) []RecognitionSiteResult {
+	for position := offset; position < len(sequence); position++ {
+		results := enzyme.mismatchResultsAt(sequence, position, isCircular, maxMismatches)
+		if len(results) > 0 {
+			return results
+		}
+	}
+	return nil
+}
+
+// Return every site in the sequence within maxMismatches substitutions of the
+// enzyme's recognition site, ordered by position.
+func (enzyme *Enzyme) SearchWithMismatches(
+	sequence string,
+	isCircular bool,
+	maxMismatches int,
+) ([]RecognitionSiteResult, error) {
+	sites := []RecognitionSiteResult{}
+
+	for position := 0; position < len(sequence); position++ {
+		sites = append(sites, enzyme.mismatchResultsAt(sequence, position, isCircular, maxMismatches)...)
+	}
+	return sites, nil
+}
+
+// Get the next site in the sequence after the offset that is within
+// maxMismatches substitutions of the recognition site of any enzyme in the
+// batch.
+func (restrictionBatch *RestrictionBatch) GetNextRecognitionSiteWithMismatches(
+	sequence string,
+	offset int,
+	isCircular bool,
+	maxMismatches int,
+) []RecognitionSiteResult {
+	for position := offset; position < len(sequence); position++ {
+		results := restrictionBatch.mismatchResultsAt(sequence, position, isCircular, maxMismatches)
+		if len(results) > 0 {
+			return results
+		}
+	}
+	return nil
+}
+
+// Return a mapping of enzyme name to every site in the sequence within
+// maxMismatches substitutions of the enzyme's recognition site.
+func (restrictionBatch *RestrictionBatch) SearchWithMismatches(
+	sequence string,
+	isCircular bool,
+	maxMismatches int,
+) (map[string][]RecognitionSiteResult, error) {
+	sitesByEnzyme := make(map[string][]RecognitionSiteResult)
+
+	for position := 0; position < len(sequence); position++ {
+		for _, result := range restrictionBatch.mismatchResultsAt(sequence, position, isCircular, maxMismatches) {
+			sitesByEnzyme[result.Enzyme.Name] = append(sitesByEnzyme[result.Enzyme.Name], result)
+		}
+	}
+	return sitesByEnzyme, nil
+}
+
+func (restrictionBatch *RestrictionBatch) mismatchResultsAt(
+	sequence string,
+	position int,
+	isCircular bool,
+	maxMismatches int,
+) []RecognitionSiteResult {
+	results := []RecognitionSiteResult{}
+	for i := range restrictionBatch.Enzymes {
+		results = append(results, restrictionBatch.Enzymes[i].mismatchResultsAt(sequence, position, isCircular, maxMismatches)...)
+	}
+	return results
+}
+
+// Return the results for the enzyme's site starting at the position on
+// either strand with at most maxMismatches substitutions.
+func (enzyme *Enzyme) mismatchResultsAt(
+	sequence string,
+	position int,
+	isCircular bool,
+	maxMismatches int,
+) []RecognitionSiteResult {
+	results := []RecognitionSiteResult{}
+
+	forwardSite := enzyme.Site
+	reverseSite := ReverseComplementSite(enzyme.Site)
+
+	if mismatches, ok := siteMismatches(sequence, forwardSite, position, isCircular, maxMismatches); ok {
+		result := newRecognitionSiteResult(enzyme, sequence, position, constants.Watson, isCircular)
+		result.Mismatches = mismatches
+		results = append(results, result)
+	}
+
+	// A palindromic site is only reported once, on the watson strand.
+	if strings.EqualFold(forwardSite, reverseSite) {
+		return results
+	}
+
+	if mismatches, ok := siteMismatches(sequence, reverseSite, position, isCircular, maxMismatches); ok {
+		// Report the mismatches relative to the site as read on the crick strand.
+		crickMismatches := make([]int, len(mismatches))
+		for i, mismatch := range mismatches {
+			crickMismatches[i] = enzyme.Length - 1 - mismatch
+		}
+		sort.Ints(crickMismatches)
+
+		result := newRecognitionSiteResult(enzyme, sequence, position, constants.Crick, isCircular)
+		result.Mismatches = crickMismatches
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// A known star activity of an enzyme. Under non-optimal conditions some
+// enzymes cleave sites that differ from their canonical recognition site.
+type StarActivity struct {
+	// The name of the star activity, e.g. EcoRI*
+	Name string
+	// The name of the enzyme showing star activity.
+	Enzyme string
+	// The relaxed recognition site cleaved with the same cut offsets as
+	// the canonical site.
+	Site string
+	// The conditions known to promote the star activity.
+	Conditions string
+}
+
+var StarActivities = map[string]StarActivity{
+	"EcoRI*": {
+		Name:       "EcoRI*",
+		Enzyme:     "EcoRI",
+		Site:       "NAATTN",
+		Conditions: "high glycerol (>5% v/v), high enzyme to DNA ratio, low ionic strength, high pH or Mn2+ in place of Mg2+",
+	},
+	"BamHI*": {
+		Name:       "BamHI*",
+		Enzyme:     "BamHI",
+		Site:       "GGNTCC",
+		Conditions: "high glycerol (>5% v/v), high enzyme to DNA ratio or prolonged incubation",
+	},
+}
+
+// Create an enzyme that recognizes the relaxed site of the star activity.
+// The parent enzyme provides the cut offsets; the relaxed site must have the
+// same length as the parent's site. The returned enzyme can be used with
+// Search, a RestrictionBatch or Dseq.Cut like any other enzyme.
+func (star StarActivity) StarEnzyme(parent Enzyme) (Enzyme, error) {
+	if len(star.Site) != parent.Length {
+		return Enzyme{}, fmt.Errorf("star site %s is not the same length as the %s site", star.Site, parent.Name)
+	}
+
+	regexpFor, regexpRev, err := CompileSiteRegexps(star.Site)
+	if err != nil {
+		return Enzyme{}, err
+	}
+
+	starEnzyme := parent
+	starEnzyme.Name = star.Name
+	starEnzyme.Site = star.Site
+	starEnzyme.Length = len(star.Site)
+	starEnzyme.RegexpFor = regexpFor
+	starEnzyme.RegexpRev = regexpRev
+	starEnzyme.OverhangLength, starEnzyme.OverhangSequence = CalculateOverhang(
+		star.Site,
+		parent.FivePrimeCutSite,
+		parent.ThreePrimeCutSite)
+
+	return starEnzyme, nil
+}
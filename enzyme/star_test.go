@@ -0,0 +1,95 @@
+package enzyme
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+)
+
+func TestSearchWithMismatches(t *testing.T) {
+	EcoRI := FIXTURES["EcoRI"]
+
+	// An exact EcoRI site, a site with one substitution (GAATTA) and a site
+	// with two substitutions (CAATTA).
+	sequence := "TTTGAATTCTTTTTGAATTATTTTTCAATTATTT"
+
+	exact, err := EcoRI.SearchWithMismatches(sequence, false, 0)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+	if len(exact) != 1 || exact[0].RecognitionSiteIndex != 3 || len(exact[0].Mismatches) != 0 {
+		t.Errorf("Expected one exact site at 3, got %v", exact)
+	}
+
+	relaxed, err := EcoRI.SearchWithMismatches(sequence, false, 1)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+	if len(relaxed) != 2 {
+		t.Fatalf("Expected 2 sites with 1 mismatch, got %d", len(relaxed))
+	}
+	if relaxed[1].RecognitionSiteIndex != 14 || !reflect.DeepEqual(relaxed[1].Mismatches, []int{5}) {
+		t.Errorf("Expected a site at 14 with a mismatch at 5, got %d %v", relaxed[1].RecognitionSiteIndex, relaxed[1].Mismatches)
+	}
+}
+
+func TestSearchWithMismatchesCrickStrand(t *testing.T) {
+	BsaI := FIXTURES["BsaI"]
+
+	// GAGTCC is GGACTC on the crick strand, one substitution from GGTCTC.
+	results := BsaI.GetNextRecognitionSiteWithMismatches("TTTGAGTCCTTT", 0, false, 1)
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].Strand != constants.Crick || results[0].RecognitionSiteIndex != 3 {
+		t.Errorf("Expected a crick site at 3, got %s %d", results[0].Strand, results[0].RecognitionSiteIndex)
+	}
+	if !reflect.DeepEqual(results[0].Mismatches, []int{2}) {
+		t.Errorf("Expected a mismatch at offset 2 of the site, got %v", results[0].Mismatches)
+	}
+}
+
+func TestBatchSearchWithMismatches(t *testing.T) {
+	batch := NewRestrictionBatch(
+		FIXTURES["EcoRI"],
+		FIXTURES["BamHI"],
+	)
+
+	sitesByEnzyme, err := batch.SearchWithMismatches("TTTGAATTATTTTGGATCATTT", false, 1)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+	if len(sitesByEnzyme["EcoRI"]) != 1 || len(sitesByEnzyme["BamHI"]) != 1 {
+		t.Errorf("Expected one relaxed site for each enzyme, got %v", sitesByEnzyme)
+	}
+
+	results := batch.GetNextRecognitionSiteWithMismatches("TTTGAATTATTTTGGATCATTT", 4, false, 1)
+	if len(results) != 1 || results[0].Enzyme.Name != "BamHI" {
+		t.Errorf("Expected the next relaxed site to be BamHI, got %v", results)
+	}
+}
+
+func TestStarActivityPreset(t *testing.T) {
+	EcoRIStar, err := StarActivities["EcoRI*"].StarEnzyme(FIXTURES["EcoRI"])
+	if err != nil {
+		t.Fatalf("Error creating EcoRI*: %v", err)
+	}
+
+	if EcoRIStar.Name != "EcoRI*" || EcoRIStar.OverhangSequence != "AATT" {
+		t.Errorf("Expected EcoRI* with an AATT overhang, got %s %s", EcoRIStar.Name, EcoRIStar.OverhangSequence)
+	}
+
+	// EcoRI* also cleaves CAATTG which EcoRI does not recognize.
+	sites, err := EcoRIStar.Search("TTTTCAATTGTTTTGAATTCTTTT", false)
+	if err != nil {
+		t.Fatalf("Error searching: %v", err)
+	}
+	if !reflect.DeepEqual(sites, []int{5, 15}) {
+		t.Errorf("Expected EcoRI* cuts at [5 15], got %v", sites)
+	}
+
+	if _, err := StarActivities["EcoRI*"].StarEnzyme(FIXTURES["BaeI"]); err == nil {
+		t.Errorf("Expected an error when the star site does not match the parent length")
+	}
+}
@@ -0,0 +1,270 @@
+package enzyme
+
+import (
+	"bufio"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+)
+
+// The number of bytes read from the reader at a time while streaming.
+const streamChunkSize = 64 * 1024
+
+// Called for every recognition site found while streaming a sequence. The
+// record is the name of the FASTA record the site was found in, or empty
+// for raw sequence and for a record with an empty header. Returning an error stops the scan.
+type SiteHandler func(record string, result RecognitionSiteResult) error
+
+// Read a linear sequence from the reader and call found for every
+// recognition site of the enzyme. See RestrictionBatch.ScanReader.
+func (enzyme *Enzyme) ScanReader(reader io.Reader, found SiteHandler) error {
+	batch := NewRestrictionBatch(*enzyme)
+	return batch.ScanReader(reader, found)
+}
+
+// Read linear sequences from the reader and call found for every
+// recognition site of every enzyme in the batch, ordered by position.
+//
+// The input is either raw sequence or FASTA. Whitespace and line breaks are
+// ignored and every FASTA record is searched on its own. Positions in the
+// results are absolute coordinates from the start of the record.
+//
+// The sequence is read in chunks and never held in memory as a whole. Only
+// the bases needed to recognize the longest site and to read the overhangs
+// of its furthest cut are kept, so chromosome scale input can be searched.
+func (restrictionBatch *RestrictionBatch) ScanReader(reader io.Reader, found SiteHandler) error {
+	scanner := newSiteStreamScanner(restrictionBatch, found)
+
+	bufferedReader := bufio.NewReaderSize(reader, streamChunkSize)
+	chunk := make([]byte, streamChunkSize)
+	for {
+		n, readErr := bufferedReader.Read(chunk)
+		if err := scanner.write(chunk[:n]); err != nil {
+			return err
+		}
+
+		if readErr == io.EOF {
+			// A header on the last line has no newline to end it.
+			if scanner.inHeader {
+				scanner.finishHeader()
+			}
+			return scanner.finishRecord()
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// The state of a streaming search of one or more records.
+type siteStreamScanner struct {
+	batch   *RestrictionBatch
+	matcher *siteMatcher
+	found   SiteHandler
+
+	// The number of bases before the start of a site and after the start
+	// of a site needed to build its results.
+	lookBehind int
+	lookAhead  int
+
+	record       string
+	inHeader     bool
+	atLineStart  bool
+	header       strings.Builder
+	state        []uint64
+	position     int
+	window       []byte
+	windowOffset int
+	pending      []patternMatch
+}
+
+func newSiteStreamScanner(restrictionBatch *RestrictionBatch, found SiteHandler) *siteStreamScanner {
+	matcher := restrictionBatch.getMatcher()
+
+	scanner := &siteStreamScanner{
+		batch:       restrictionBatch,
+		matcher:     matcher,
+		found:       found,
+		lookAhead:   matcher.maxLength,
+		atLineStart: true,
+		state:       matcher.newState(),
+	}
+
+	for i := range restrictionBatch.Enzymes {
+		enzyme := &restrictionBatch.Enzymes[i]
+		for _, cut := range enzymeCutReach(enzyme) {
+			if -cut > scanner.lookBehind {
+				scanner.lookBehind = -cut
+			}
+			if cut > scanner.lookAhead {
+				scanner.lookAhead = cut
+			}
+		}
+	}
+
+	return scanner
+}
+
+// Return the positions of every cut of the enzyme relative to the start of
+// its site on either strand.
+func enzymeCutReach(enzyme *Enzyme) []int {
+	cuts := []int{}
+	for _, strand := range []constants.Strand{constants.Watson, constants.Crick} {
+		watsonIndex, crickIndex := enzyme.GetCutSitePositions(0, strand)
+		cuts = append(cuts, watsonIndex, crickIndex)
+		if enzyme.HasSecondCut() {
+			watsonIndex, crickIndex = enzyme.GetSecondCutSitePositions(0, strand)
+			cuts = append(cuts, watsonIndex, crickIndex)
+		}
+	}
+	return cuts
+}
+
+// Process a chunk of the input.
+func (scanner *siteStreamScanner) write(chunk []byte) error {
+	for _, char := range chunk {
+		if scanner.inHeader {
+			if char == '\n' {
+				scanner.finishHeader()
+			} else {
+				scanner.header.WriteByte(char)
+			}
+			continue
+		}
+
+		if scanner.atLineStart && char == '>' {
+			if err := scanner.finishRecord(); err != nil {
+				return err
+			}
+			scanner.inHeader = true
+			scanner.header.Reset()
+			continue
+		}
+
+		switch char {
+		case '\n':
+			scanner.atLineStart = true
+			continue
+		case '\r', ' ', '\t':
+			continue
+		}
+		scanner.atLineStart = false
+
+		if err := scanner.addBase(char); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Name the record after the first word of its header.
+func (scanner *siteStreamScanner) finishHeader() {
+	scanner.record = ""
+	if fields := strings.Fields(scanner.header.String()); len(fields) > 0 {
+		scanner.record = fields[0]
+	}
+	scanner.inHeader = false
+	scanner.atLineStart = true
+}
+
+// Add the next base of the record and report the sites that have enough
+// context to build their results.
+func (scanner *siteStreamScanner) addBase(base byte) error {
+	scanner.window = append(scanner.window, base)
+
+	scanner.matcher.step(scanner.state, base)
+	scanner.matcher.matches(scanner.state, func(pattern int) {
+		start := scanner.position - scanner.matcher.patterns[pattern].length + 1
+
+		// Longer sites are found after shorter sites that start later.
+		// Keep the pending sites ordered by their start.
+		i := len(scanner.pending)
+		for i > 0 && scanner.pending[i-1].start > start {
+			i--
+		}
+		scanner.pending = slices.Insert(scanner.pending, i, patternMatch{start: start, pattern: pattern})
+	})
+	scanner.position++
+
+	if err := scanner.emitPending(scanner.position - scanner.lookAhead); err != nil {
+		return err
+	}
+
+	// Drop the bases that can no longer be needed, once enough have built
+	// up that the copy is amortized.
+	keep := scanner.lookBehind + scanner.lookAhead
+	if len(scanner.window) > 2*keep+streamChunkSize {
+		drop := len(scanner.window) - keep
+		scanner.window = append(scanner.window[:0], scanner.window[drop:]...)
+		scanner.windowOffset += drop
+	}
+	return nil
+}
+
+// Report the pending sites that start at or before the last position.
+func (scanner *siteStreamScanner) emitPending(lastStart int) error {
+	for len(scanner.pending) > 0 && scanner.pending[0].start <= lastStart {
+		siteEnd := 1
+		for siteEnd < len(scanner.pending) && scanner.pending[siteEnd].start == scanner.pending[0].start {
+			siteEnd++
+		}
+
+		if err := scanner.emit(scanner.pending[:siteEnd]); err != nil {
+			return err
+		}
+		scanner.pending = scanner.pending[siteEnd:]
+	}
+	return nil
+}
+
+// Build and report the results for matches starting at the same position.
+// The results are built against the bases around the site and then moved
+// to absolute coordinates.
+func (scanner *siteStreamScanner) emit(matches []patternMatch) error {
+	start := matches[0].start
+
+	windowStart := max(start-scanner.lookBehind, scanner.windowOffset)
+	windowEnd := min(start+scanner.lookAhead, scanner.windowOffset+len(scanner.window))
+	window := string(scanner.window[windowStart-scanner.windowOffset : windowEnd-scanner.windowOffset])
+
+	localMatches := make([]patternMatch, len(matches))
+	for i, match := range matches {
+		localMatches[i] = patternMatch{start: start - windowStart, pattern: match.pattern}
+	}
+
+	// The window is read as a linear sequence so, as for a linear Search,
+	// overhangs beyond either end of the record are left empty.
+	results := scanner.batch.appendResultsForMatches(scanner.matcher, nil, window, localMatches, false)
+	for _, result := range results {
+		result.RecognitionSiteIndex += windowStart
+		result.WatsonCutIndex += windowStart
+		result.CrickCutIndex += windowStart
+		if result.HasSecondCut {
+			result.WatsonCutIndex2 += windowStart
+			result.CrickCutIndex2 += windowStart
+		}
+
+		if err := scanner.found(scanner.record, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Report the remaining sites of the current record and reset the scanner
+// for the next one.
+func (scanner *siteStreamScanner) finishRecord() error {
+	if err := scanner.emitPending(scanner.position); err != nil {
+		return err
+	}
+
+	clear(scanner.state)
+	scanner.record = ""
+	scanner.position = 0
+	scanner.window = scanner.window[:0]
+	scanner.windowOffset = 0
+	scanner.pending = scanner.pending[:0]
+	return nil
+}
@@ -0,0 +1,116 @@
+package enzyme
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestScanReaderMatchesFindAll(t *testing.T) {
+	genome := randomSequence(1, 300_000)
+
+	batch := benchmarkBatch()
+	expected := batch.FindAll(genome, false)
+
+	// Read one byte at a time so that sites straddle every chunk boundary.
+	results := []RecognitionSiteResult{}
+	err := batch.ScanReader(iotest.OneByteReader(strings.NewReader(genome)), func(record string, result RecognitionSiteResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error scanning: %v", err)
+	}
+
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d sites, got %d", len(expected), len(results))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(results[i], expected[i]) {
+			t.Fatalf("Expected %v, got %v", expected[i], results[i])
+		}
+	}
+}
+
+func TestScanReaderFasta(t *testing.T) {
+	fasta := ">first plasmid\nTTTGAA\nTTCTTT\n>second\nGGTCTCAAAA\r\nAAAAGAATTC\n"
+
+	EcoRI := FIXTURES["EcoRI"]
+	batch := NewRestrictionBatch(EcoRI, FIXTURES["BsaI"])
+
+	type site struct {
+		record string
+		name   string
+		index  int
+	}
+	sites := []site{}
+	err := batch.ScanReader(strings.NewReader(fasta), func(record string, result RecognitionSiteResult) error {
+		sites = append(sites, site{record, result.Enzyme.Name, result.RecognitionSiteIndex})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error scanning: %v", err)
+	}
+
+	expected := []site{
+		{"first", "EcoRI", 3},
+		{"second", "BsaI", 0},
+		{"second", "EcoRI", 14},
+	}
+	if !reflect.DeepEqual(sites, expected) {
+		t.Errorf("Expected %v, got %v", expected, sites)
+	}
+}
+
+func TestScanReaderStopsOnError(t *testing.T) {
+	EcoRI := FIXTURES["EcoRI"]
+	stop := errors.New("stop")
+
+	count := 0
+	err := EcoRI.ScanReader(strings.NewReader("GAATTCGAATTCGAATTC"), func(record string, result RecognitionSiteResult) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("Expected the scan to stop after 1 site, got %d sites and %v", count, err)
+	}
+}
+
+func TestScanReaderEmptyHeader(t *testing.T) {
+	EcoRI := FIXTURES["EcoRI"]
+
+	for _, fasta := range []string{">\nTTGAATTC\n", ">  \t\nTTGAATTC\n"} {
+		records := []string{}
+		err := EcoRI.ScanReader(strings.NewReader(fasta), func(record string, result RecognitionSiteResult) error {
+			records = append(records, record)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Error scanning %q: %v", fasta, err)
+		}
+		if !reflect.DeepEqual(records, []string{""}) {
+			t.Errorf("Expected one site in an unnamed record of %q, got %v", fasta, records)
+		}
+	}
+}
+
+func TestScanReaderHeaderAtEnd(t *testing.T) {
+	EcoRI := FIXTURES["EcoRI"]
+
+	// The last record has a header without a newline and no sequence.
+	for _, fasta := range []string{">first\nTTGAATTC\n>second", ">first\nTTGAATTC\n>"} {
+		records := []string{}
+		err := EcoRI.ScanReader(iotest.OneByteReader(strings.NewReader(fasta)), func(record string, result RecognitionSiteResult) error {
+			records = append(records, record)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Error scanning %q: %v", fasta, err)
+		}
+		if !reflect.DeepEqual(records, []string{"first"}) {
+			t.Errorf("Expected one site in the first record of %q, got %v", fasta, records)
+		}
+	}
+}
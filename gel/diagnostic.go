@@ -0,0 +1,296 @@
+package gel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+// Options for designing diagnostic digests.
+type DiagnosticOptions struct {
+	// The agarose percentage of the gel and the distance, as a fraction of
+	// the length of the gel, two bands must be apart to be told apart.
+	// Default to 1% and DefaultResolution.
+	Percentage float64
+	Resolution float64
+
+	// Bands smaller than this are too faint to see and are ignored.
+	MinBandSize int
+
+	// The most cuts a digest may make in any construct, to keep the
+	// patterns readable. Defaults to 6.
+	MaxCuts int
+
+	// Also try every pair of enzymes as a double digest.
+	DoubleDigests bool
+
+	// Return true if the enzyme is sold by at least one supplier, e.g.
+	// db.IsCommerciallyAvailable. Defaults to treating every enzyme as
+	// commercially available.
+	IsCommerciallyAvailable func(name string) bool
+}
+
+// A digest whose band patterns tell every construct apart.
+type DiagnosticDigest struct {
+	Enzymes []string
+
+	// True if every enzyme of the digest is sold by at least one supplier.
+	Commercial bool
+
+	// The distance, as a fraction of the length of the gel, between the
+	// patterns of the two constructs that are hardest to tell apart. Two
+	// patterns are as far apart as the band of either that is furthest from
+	// any band of the other with as many fragments. A band without such a
+	// band in the other pattern, e.g. a doublet where the other pattern has
+	// a single band, is as far as the length of the gel.
+	Separation float64
+
+	// The expected lane of each construct, in the order of the constructs.
+	Lanes []Lane
+}
+
+// Return a gel of the digest's lanes next to the ladder.
+func (digest *DiagnosticDigest) Gel(percentage float64, ladder Ladder) *Gel {
+	gel := NewGel(percentage)
+	gel.AddLadder(ladder)
+	for _, lane := range digest.Lanes {
+		gel.AddLane(lane)
+	}
+	return gel
+}
+
+/*
+Find the single, and optionally double, digests of the enzymes whose band
+patterns tell every pair of constructs apart, e.g. a parent plasmid and
+clones with an insert in either orientation. The enzymes to try are usually
+the commercially available enzymes of the database with known cut positions,
+e.g. from db.Select.
+
+The digest must cut every construct at least once and at most MaxCuts
+times. Enzymes that cut every construct at the same positions, such as
+isoschizomers, are only tried once; the commercially available enzyme, and
+then the first by name, is kept. Digests are ranked with commercially
+available digests first, then by separation, then single before double
+digests and then by name.
+*/
+func DesignDiagnosticDigests(
+	constructs []enzyme.SequenceInput,
+	enzymes []enzyme.Enzyme,
+	options DiagnosticOptions,
+) []DiagnosticDigest {
+	if options.Percentage == 0 {
+		options.Percentage = 1.0
+	}
+	if options.Resolution == 0 {
+		options.Resolution = DefaultResolution
+	}
+	if options.MaxCuts == 0 {
+		options.MaxCuts = 6
+	}
+	if options.IsCommerciallyAvailable == nil {
+		options.IsCommerciallyAvailable = func(string) bool { return true }
+	}
+
+	batch := enzyme.NewRestrictionBatch(enzymes...)
+
+	// The cuts of each enzyme in each construct.
+	cutsByEnzyme := make(map[string][][]int, len(batch.Enzymes))
+	for i, construct := range constructs {
+		for _, result := range batch.FindAll(construct.Sequence, construct.IsCircular) {
+			cuts, ok := cutsByEnzyme[result.Enzyme.Name]
+			if !ok {
+				cuts = make([][]int, len(constructs))
+				cutsByEnzyme[result.Enzyme.Name] = cuts
+			}
+			cuts[i] = append(cuts[i], result.WatsonCutIndexes()...)
+		}
+	}
+
+	singles := diagnosticCandidates(batch.Enzymes, constructs, cutsByEnzyme, options)
+
+	digests := []DiagnosticDigest{}
+	addDigest := func(names []string, cuts [][]int) {
+		if digest, ok := evaluateDigest(names, constructs, cuts, options); ok {
+			digests = append(digests, digest)
+		}
+	}
+
+	for _, single := range singles {
+		addDigest([]string{single.name}, single.cuts)
+	}
+	if options.DoubleDigests {
+		for i := range singles {
+			for j := i + 1; j < len(singles); j++ {
+				cuts := make([][]int, len(constructs))
+				tooMany := false
+				for k := range constructs {
+					cuts[k] = append(append([]int{}, singles[i].cuts[k]...), singles[j].cuts[k]...)
+					cuts[k] = enzyme.NormalizeCuts(cuts[k], len(constructs[k].Sequence), constructs[k].IsCircular)
+					tooMany = tooMany || len(cuts[k]) > options.MaxCuts
+				}
+				if !tooMany {
+					addDigest([]string{singles[i].name, singles[j].name}, cuts)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(digests, func(i, j int) bool {
+		a, b := &digests[i], &digests[j]
+		switch {
+		case a.Commercial != b.Commercial:
+			return a.Commercial
+		case a.Separation != b.Separation:
+			return a.Separation > b.Separation
+		case len(a.Enzymes) != len(b.Enzymes):
+			return len(a.Enzymes) < len(b.Enzymes)
+		default:
+			return strings.Join(a.Enzymes, "+") < strings.Join(b.Enzymes, "+")
+		}
+	})
+	return digests
+}
+
+// An enzyme and its normalized cuts in each construct.
+type diagnosticCandidate struct {
+	name string
+	cuts [][]int
+}
+
+// Return the enzymes that cut a construct and cut none more than maxCuts
+// times, keeping one enzyme for each distinct set of cuts.
+func diagnosticCandidates(
+	enzymes []enzyme.Enzyme,
+	constructs []enzyme.SequenceInput,
+	cutsByEnzyme map[string][][]int,
+	options DiagnosticOptions,
+) []diagnosticCandidate {
+	sorted := append([]enzyme.Enzyme{}, enzymes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := options.IsCommerciallyAvailable(sorted[i].Name), options.IsCommerciallyAvailable(sorted[j].Name)
+		if a != b {
+			return a
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	candidates := []diagnosticCandidate{}
+	seen := map[string]bool{}
+	for _, ez := range sorted {
+		rawCuts, ok := cutsByEnzyme[ez.Name]
+		if !ok {
+			continue
+		}
+
+		cuts := make([][]int, len(constructs))
+		signature := []string{}
+		valid := true
+		for i, construct := range constructs {
+			cuts[i] = enzyme.NormalizeCuts(rawCuts[i], len(construct.Sequence), construct.IsCircular)
+			if len(cuts[i]) > options.MaxCuts {
+				valid = false
+				break
+			}
+			signature = append(signature, fmt.Sprint(cuts[i]))
+		}
+		if !valid {
+			continue
+		}
+
+		key := strings.Join(signature, "|")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		candidates = append(candidates, diagnosticCandidate{ez.Name, cuts})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].name < candidates[j].name
+	})
+	return candidates
+}
+
+// Return the digest of the constructs with the cuts if its patterns tell
+// every pair of constructs apart.
+func evaluateDigest(names []string, constructs []enzyme.SequenceInput, cuts [][]int, options DiagnosticOptions) (DiagnosticDigest, bool) {
+	digest := DiagnosticDigest{
+		Enzymes:    names,
+		Commercial: true,
+		Separation: math.Inf(1),
+		Lanes:      make([]Lane, len(constructs)),
+	}
+	for _, name := range names {
+		digest.Commercial = digest.Commercial && options.IsCommerciallyAvailable(name)
+	}
+
+	// Uncut plasmids run as several topological forms that do not follow
+	// their size.
+	for i := range constructs {
+		if len(cuts[i]) == 0 {
+			return DiagnosticDigest{}, false
+		}
+	}
+
+	gel := NewGel(options.Percentage)
+	gel.Resolution = options.Resolution
+
+	patterns := make([][]patternBand, len(constructs))
+	for i, construct := range constructs {
+		sizes := fragmentSizes(len(construct.Sequence), cuts[i], construct.IsCircular)
+		digest.Lanes[i] = NewLane(construct.Name, sizes, DefaultLaneMass)
+		for _, band := range gel.Bands(digest.Lanes[i]) {
+			if band.Sizes[0] >= options.MinBandSize {
+				patterns[i] = append(patterns[i], patternBand{band.Migration, len(band.Sizes)})
+			}
+		}
+	}
+
+	for i := range constructs {
+		for j := i + 1; j < len(constructs); j++ {
+			separation := patternDistance(patterns[i], patterns[j])
+			if separation <= options.Resolution {
+				return DiagnosticDigest{}, false
+			}
+			digest.Separation = math.Min(digest.Separation, separation)
+		}
+	}
+	return digest, true
+}
+
+// A band of a pattern and the number of fragments in it.
+type patternBand struct {
+	migration float64
+	count     int
+}
+
+// Return the largest distance from a band of either pattern to the nearest
+// band of the other with as many fragments. A band with no such band in the
+// other pattern, e.g. a doublet where the other pattern has a single band, is
+// told apart by its brightness and is a distance of 1 away.
+func patternDistance(a []patternBand, b []patternBand) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		if len(a) == len(b) {
+			return 0
+		}
+		return 1
+	}
+
+	furthest := func(from []patternBand, to []patternBand) float64 {
+		distance := 0.0
+		for _, x := range from {
+			nearest := 1.0
+			for _, y := range to {
+				if x.count == y.count {
+					nearest = math.Min(nearest, math.Abs(x.migration-y.migration))
+				}
+			}
+			distance = math.Max(distance, nearest)
+		}
+		return distance
+	}
+	return math.Max(furthest(a, b), furthest(b, a))
+}
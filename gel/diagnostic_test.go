@@ -0,0 +1,127 @@
+package gel
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/db"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+// Return a random sequence of A and T, which has no site of EcoRI, BamHI or
+// HindIII on either strand.
+func randomATSequence(random *rand.Rand, length int) string {
+	bases := make([]byte, length)
+	for i := range bases {
+		bases[i] = "AT"[random.Intn(2)]
+	}
+	return string(bases)
+}
+
+// A 3 kb parent plasmid with an EcoRI site and clones with a 1 kb insert,
+// with a BamHI site near one end, in either orientation.
+func orientationConstructs() []enzyme.SequenceInput {
+	random := rand.New(rand.NewSource(1))
+	backbone := randomATSequence(random, 3000)
+	backbone = backbone[:100] + "GAATTC" + backbone[106:]
+	insert := randomATSequence(random, 1000)
+	insert = insert[:200] + "GGATCC" + insert[206:]
+
+	return []enzyme.SequenceInput{
+		{Name: "parent", Sequence: backbone, IsCircular: true},
+		{Name: "forward", Sequence: backbone[:1000] + insert + backbone[1000:], IsCircular: true},
+		{Name: "reverse", Sequence: backbone[:1000] + enzyme.ReverseComplementSite(insert) + backbone[1000:], IsCircular: true},
+	}
+}
+
+func TestDesignDiagnosticDigests(t *testing.T) {
+	constructs := orientationConstructs()
+	enzymes := []enzyme.Enzyme{db.Enzymes["EcoRI"], db.Enzymes["BamHI"], db.Enzymes["HindIII"]}
+	options := DiagnosticOptions{IsCommerciallyAvailable: db.IsCommerciallyAvailable}
+
+	// EcoRI cannot tell the orientations apart and BamHI does not cut the
+	// parent.
+	if digests := DesignDiagnosticDigests(constructs, enzymes, options); len(digests) != 0 {
+		t.Errorf("Expected no single digest to tell the constructs apart, got %v", digests)
+	}
+
+	options.DoubleDigests = true
+	digests := DesignDiagnosticDigests(constructs, enzymes, options)
+	if len(digests) != 1 {
+		t.Fatalf("Expected one double digest, got %v", digests)
+	}
+
+	digest := digests[0]
+	if !reflect.DeepEqual(digest.Enzymes, []string{"BamHI", "EcoRI"}) || !digest.Commercial {
+		t.Errorf("Expected the commercial BamHI and EcoRI digest, got %v", digest)
+	}
+	if digest.Separation <= DefaultResolution {
+		t.Errorf("Expected a separation above the resolution, got %f", digest.Separation)
+	}
+
+	expected := [][]int{{3000}, {1100, 2900}, {1694, 2306}}
+	for i, lane := range digest.Lanes {
+		sizes := []int{}
+		for _, fragment := range lane.Fragments {
+			sizes = append(sizes, fragment.Size)
+		}
+		if lane.Name != constructs[i].Name || !reflect.DeepEqual(sizes, expected[i]) {
+			t.Errorf("Expected lane %s of %v, got %s of %v", constructs[i].Name, expected[i], lane.Name, sizes)
+		}
+	}
+
+	gel := digest.Gel(1.0, OneKbLadder)
+	if len(gel.Lanes) != 4 || !gel.Lanes[0].IsLadder {
+		t.Errorf("Expected a ladder and three lanes, got %v", gel.Lanes)
+	}
+	if svg, err := gel.SVG(); err != nil || !strings.Contains(svg, "reverse") {
+		t.Errorf("Expected the lanes to be labelled in the SVG")
+	}
+
+	// At a resolution of the whole gel the two bands of each clone run as
+	// one doublet, so the orientations cannot be told apart.
+	options.Resolution = 1
+	if digests := DesignDiagnosticDigests(constructs, enzymes, options); len(digests) != 0 {
+		t.Errorf("Expected no digest at a resolution of %f, got %v", options.Resolution, digests)
+	}
+}
+
+func TestDiagnosticIsoschizomers(t *testing.T) {
+	isoschizomer := db.Enzymes["BamHI"]
+	isoschizomer.Name = "AbcBamHI"
+	digests := DesignDiagnosticDigests(
+		orientationConstructs(),
+		[]enzyme.Enzyme{db.Enzymes["BamHI"], db.Enzymes["EcoRI"], isoschizomer},
+		DiagnosticOptions{DoubleDigests: true, IsCommerciallyAvailable: db.IsCommerciallyAvailable},
+	)
+
+	// The isoschizomer cuts at the same positions and is not sold, so only
+	// BamHI is tried.
+	if len(digests) != 1 || !reflect.DeepEqual(digests[0].Enzymes, []string{"BamHI", "EcoRI"}) {
+		t.Errorf("Expected only the BamHI and EcoRI digest, got %v", digests)
+	}
+}
+
+func TestPatternDistance(t *testing.T) {
+	tests := []struct {
+		a, b     []patternBand
+		expected float64
+	}{
+		{[]patternBand{{0.2, 1}, {0.5, 1}}, []patternBand{{0.2, 1}, {0.5, 1}}, 0},
+		{[]patternBand{{0.2, 1}, {0.5, 1}}, []patternBand{{0.2, 1}}, 0.3},
+		{[]patternBand{{0.2, 1}}, []patternBand{{0.25, 1}, {0.6, 1}}, 0.4},
+		// A doublet and a single band at the same size are told apart.
+		{[]patternBand{{0.3, 2}}, []patternBand{{0.3, 1}}, 1},
+		{[]patternBand{{0.3, 2}, {0.5, 1}}, []patternBand{{0.32, 2}, {0.5, 1}}, 0.02},
+		{nil, []patternBand{{0.3, 1}}, 1},
+		{nil, nil, 0},
+	}
+	for _, test := range tests {
+		if distance := patternDistance(test.a, test.b); math.Abs(distance-test.expected) > 1e-9 {
+			t.Errorf("Expected a distance of %f between %v and %v, got %f", test.expected, test.a, test.b, distance)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Package gel simulates agarose gel electrophoresis of restriction digests and
+renders the expected pattern of bands as an SVG image.
+*/
+package gel
+
+import (
+	"math"
+	"sort"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+	"github.com/rmcl/restriction-enzymes/sequence"
+)
+
+// The mass of DNA, in ng, loaded in a lane when none is given.
+const DefaultLaneMass = 500.0
+
+// The distance, as a fraction of the length of the gel, within which bands
+// can not be told apart and are merged.
+const DefaultResolution = 0.01
+
+// A DNA fragment loaded on a gel.
+type Fragment struct {
+	Size int
+
+	// The mass of the fragment in ng.
+	Mass float64
+}
+
+// A lane of a gel.
+type Lane struct {
+	Name      string
+	Fragments []Fragment
+
+	// True if the lane is loaded with a ladder. The sizes of the first
+	// ladder on a gel are labelled.
+	IsLadder bool
+}
+
+// Create a lane of fragments of the given sizes from a digest of the given
+// total mass in ng. Every fragment of a digest is present in the same molar
+// amount, so each fragment's mass is proportional to its size.
+func NewLane(name string, sizes []int, mass float64) Lane {
+	total := 0
+	for _, size := range sizes {
+		total += size
+	}
+
+	lane := Lane{Name: name, Fragments: make([]Fragment, 0, len(sizes))}
+	for _, size := range sizes {
+		fragment := Fragment{Size: size}
+		if total > 0 {
+			fragment.Mass = mass * float64(size) / float64(total)
+		}
+		lane.Fragments = append(lane.Fragments, fragment)
+	}
+	return lane
+}
+
+// Create a lane from the fragments returned by Dseq.Cut. The size of a
+// fragment is the length of its watson strand.
+func LaneFromDseqs(name string, fragments []sequence.Dseq, mass float64) Lane {
+	sizes := make([]int, len(fragments))
+	for i := range fragments {
+		sizes[i] = len(fragments[i].Watson)
+	}
+	return NewLane(name, sizes, mass)
+}
+
+// Create a lane from the watson strand cut positions returned by
+// RestrictionBatch.Search for a sequence of the given length. The cuts of
+// every enzyme are combined, as in a digest with all of them.
+func LaneFromSearch(name string, length int, cutsByEnzyme map[string][]int, geometry constants.SequenceGeometry, mass float64) Lane {
+	isCircular := geometry == constants.Circular
+
+	cuts := []int{}
+	for _, enzymeCuts := range cutsByEnzyme {
+		cuts = append(cuts, enzymeCuts...)
+	}
+	return NewLane(name, fragmentSizes(length, enzyme.NormalizeCuts(cuts, length, isCircular), isCircular), mass)
+}
+
+// Return the sizes of the fragments between the sorted cuts.
+func fragmentSizes(length int, cuts []int, isCircular bool) []int {
+	if len(cuts) == 0 {
+		return []int{length}
+	}
+
+	sizes := []int{}
+	if !isCircular {
+		sizes = append(sizes, cuts[0])
+	}
+	for i := 1; i < len(cuts); i++ {
+		sizes = append(sizes, cuts[i]-cuts[i-1])
+	}
+	if isCircular {
+		sizes = append(sizes, length-cuts[len(cuts)-1]+cuts[0])
+	} else {
+		sizes = append(sizes, length-cuts[len(cuts)-1])
+	}
+	return sizes
+}
+
+// A ladder of DNA fragments of known size.
+type Ladder struct {
+	Name string
+
+	// The bands of the ladder and their mass, in ng, in the recommended
+	// load.
+	Bands []Fragment
+}
+
+// Return a lane loaded with the ladder.
+func (ladder Ladder) Lane() Lane {
+	return Lane{Name: ladder.Name, Fragments: append([]Fragment{}, ladder.Bands...), IsLadder: true}
+}
+
+var (
+	// A 1 kb ladder, 0.5 to 10 kb, as sold by NEB (N3232), 0.5 µg per lane.
+	OneKbLadder = Ladder{Name: "1 kb", Bands: []Fragment{
+		{10000, 42}, {8000, 42}, {6000, 50}, {5000, 42}, {4000, 33},
+		{3000, 125}, {2000, 48}, {1500, 36}, {1000, 42}, {500, 42},
+	}}
+
+	// A 100 bp ladder, 100 to 1517 bp, as sold by NEB (N3231), 0.5 µg per
+	// lane.
+	OneHundredBpLadder = Ladder{Name: "100 bp", Bands: []Fragment{
+		{1517, 45}, {1200, 35}, {1000, 95}, {900, 27}, {800, 24}, {700, 21},
+		{600, 18}, {517, 49}, {500, 48}, {400, 38}, {300, 29}, {200, 25}, {100, 48},
+	}}
+
+	// Lambda DNA digested with HindIII, 0.5 µg per lane. The 4361 bp band
+	// is fainter than expected when the cohesive ends of the 23130 and 4361
+	// bp fragments anneal, which is not modelled.
+	LambdaHindIIILadder = lambdaHindIII()
+)
+
+func lambdaHindIII() Ladder {
+	const lambdaLength = 48502
+	sizes := []int{23130, 9416, 6557, 4361, 2322, 2027, 564, 125}
+
+	ladder := Ladder{Name: "λ-HindIII"}
+	for _, size := range sizes {
+		ladder.Bands = append(ladder.Bands, Fragment{size, DefaultLaneMass * float64(size) / lambdaLength})
+	}
+	return ladder
+}
+
+// The range of fragment sizes, in bp, separated by gels of a given agarose
+// percentage. Sizes at the upper limit stay near the wells and sizes at the
+// lower limit run near the dye front.
+type separationRange struct {
+	percentage float64
+	smallest   float64
+	largest    float64
+}
+
+var separationRanges = []separationRange{
+	{0.5, 1000, 30000},
+	{0.7, 800, 12000},
+	{1.0, 500, 10000},
+	{1.2, 400, 7000},
+	{1.5, 200, 3000},
+	{2.0, 100, 2000},
+	{3.0, 50, 1000},
+}
+
+// Return the size range separated by a gel, interpolating the log of the
+// sizes between the nearest tabulated percentages.
+func separationRangeFor(percentage float64) (float64, float64) {
+	first, last := separationRanges[0], separationRanges[len(separationRanges)-1]
+	if percentage <= first.percentage {
+		return first.smallest, first.largest
+	}
+	if percentage >= last.percentage {
+		return last.smallest, last.largest
+	}
+
+	for i := 1; i < len(separationRanges); i++ {
+		lower, upper := separationRanges[i-1], separationRanges[i]
+		if percentage > upper.percentage {
+			continue
+		}
+		fraction := (percentage - lower.percentage) / (upper.percentage - lower.percentage)
+		interpolate := func(a float64, b float64) float64 {
+			return math.Pow(10, math.Log10(a)+fraction*(math.Log10(b)-math.Log10(a)))
+		}
+		return interpolate(lower.smallest, upper.smallest), interpolate(lower.largest, upper.largest)
+	}
+	return last.smallest, last.largest
+}
+
+// The migration of the smallest and largest separated sizes, as a fraction
+// of the length of the gel from the wells.
+const (
+	largestMigration  = 0.1
+	smallestMigration = 0.9
+)
+
+// Return the distance a fragment migrates in a gel of the given agarose
+// percentage, as a fraction of the length of the gel from the wells (0) to
+// the dye front (1). The semi-log model places the distance linearly in the
+// log of the size across the separation range of the gel. Fragments beyond
+// the range continue on the same line but are kept on the gel.
+func Migration(size int, percentage float64) float64 {
+	if size <= 0 {
+		return 1
+	}
+	smallest, largest := separationRangeFor(percentage)
+
+	slope := (smallestMigration - largestMigration) / (math.Log10(largest) - math.Log10(smallest))
+	distance := largestMigration + slope*(math.Log10(largest)-math.Log10(float64(size)))
+	return math.Min(math.Max(distance, 0.02), 0.98)
+}
+
+// A band on a gel. Fragments that run within the resolution of the gel of
+// each other are merged into one band.
+type Band struct {
+	Sizes []int
+
+	// The total mass of the band in ng and its distance from the wells as a
+	// fraction of the length of the gel.
+	Mass      float64
+	Migration float64
+}
+
+// A simulated agarose gel.
+type Gel struct {
+	Percentage float64
+	Resolution float64
+	Lanes      []Lane
+}
+
+// Create a gel of the given agarose percentage, e.g. 1.0, with the default
+// resolution.
+func NewGel(percentage float64, lanes ...Lane) *Gel {
+	return &Gel{Percentage: percentage, Resolution: DefaultResolution, Lanes: lanes}
+}
+
+// Add a lane to the gel.
+func (gel *Gel) AddLane(lane Lane) {
+	gel.Lanes = append(gel.Lanes, lane)
+}
+
+// Add a lane loaded with the ladder to the gel.
+func (gel *Gel) AddLadder(ladder Ladder) {
+	gel.Lanes = append(gel.Lanes, ladder.Lane())
+}
+
+// Return the bands of the lane on the gel, from the wells to the front.
+func (gel *Gel) Bands(lane Lane) []Band {
+	fragments := append([]Fragment{}, lane.Fragments...)
+	sort.SliceStable(fragments, func(i, j int) bool {
+		return fragments[i].Size > fragments[j].Size
+	})
+
+	bands := []Band{}
+	for _, fragment := range fragments {
+		migration := Migration(fragment.Size, gel.Percentage)
+		if len(bands) > 0 {
+			last := &bands[len(bands)-1]
+			if migration-last.Migration <= gel.Resolution {
+				// Place the merged band at the mass weighted mean distance.
+				total := last.Mass + fragment.Mass
+				if total > 0 {
+					last.Migration = (last.Migration*last.Mass + migration*fragment.Mass) / total
+				}
+				last.Sizes = append(last.Sizes, fragment.Size)
+				last.Mass = total
+				continue
+			}
+		}
+		bands = append(bands, Band{Sizes: []int{fragment.Size}, Mass: fragment.Mass, Migration: migration})
+	}
+	return bands
+}
@@ -0,0 +1,141 @@
+package gel
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/db"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+	"github.com/rmcl/restriction-enzymes/sequence"
+)
+
+func TestMigration(t *testing.T) {
+	previous := 0.0
+	for _, size := range []int{20000, 10000, 5000, 2000, 1000, 500, 100} {
+		migration := Migration(size, 1.0)
+		if migration <= previous {
+			t.Errorf("Expected %d bp to run further than larger fragments, got %f", size, migration)
+		}
+		if migration < 0 || migration > 1 {
+			t.Errorf("Expected %d bp to stay on the gel, got %f", size, migration)
+		}
+		previous = migration
+	}
+
+	if migration := Migration(10000, 1.0); math.Abs(migration-largestMigration) > 1e-9 {
+		t.Errorf("Expected the largest separated size to run %f, got %f", largestMigration, migration)
+	}
+	if migration := Migration(500, 1.0); math.Abs(migration-smallestMigration) > 1e-9 {
+		t.Errorf("Expected the smallest separated size to run %f, got %f", smallestMigration, migration)
+	}
+
+	// A small fragment runs further on a dilute gel than on a concentrated one.
+	if Migration(300, 0.7) <= Migration(300, 2.0) {
+		t.Errorf("Expected 300 bp to run further on a 0.7%% gel than on a 2%% gel")
+	}
+
+	smallest, largest := separationRangeFor(1.1)
+	if smallest >= 500 || smallest <= 400 || largest >= 10000 || largest <= 7000 {
+		t.Errorf("Expected the range of a 1.1%% gel to lie between 1%% and 1.2%%, got %f to %f", smallest, largest)
+	}
+}
+
+func TestNewLane(t *testing.T) {
+	lane := NewLane("digest", []int{3000, 1000}, 400)
+	expected := []Fragment{{3000, 300}, {1000, 100}}
+	if !reflect.DeepEqual(lane.Fragments, expected) {
+		t.Errorf("Expected fragments %v, got %v", expected, lane.Fragments)
+	}
+}
+
+func TestLaneFromSearch(t *testing.T) {
+	// EcoRI cuts after 101 and 301 in a 1000 bp circle.
+	watson := []byte{}
+	for len(watson) < 1000 {
+		watson = append(watson, 'A', 'C')
+	}
+	copy(watson[100:], "GAATTC")
+	copy(watson[300:], "GAATTC")
+
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["EcoRI"])
+	cuts, err := batch.Search(string(watson), true)
+	if err != nil {
+		t.Fatalf("Failed to search: %s", err)
+	}
+
+	lane := LaneFromSearch("EcoRI", len(watson), cuts, constants.Circular, DefaultLaneMass)
+	sizes := []int{}
+	for _, fragment := range lane.Fragments {
+		sizes = append(sizes, fragment.Size)
+	}
+	if !reflect.DeepEqual(sizes, []int{200, 800}) {
+		t.Errorf("Expected fragments of 200 and 800 bp, got %v", sizes)
+	}
+
+	lane = LaneFromSearch("EcoRI", len(watson), cuts, constants.Linear, DefaultLaneMass)
+	if len(lane.Fragments) != 3 || lane.Fragments[0].Size != 101 {
+		t.Errorf("Expected 3 linear fragments starting with 101 bp, got %v", lane.Fragments)
+	}
+}
+
+func TestLaneFromDseqs(t *testing.T) {
+	dSeq := sequence.NewFromWatsonStrand("AAAAAAAAAAGAATTCAAAAAAAAAA", constants.Linear)
+	EcoRI := db.Enzymes["EcoRI"]
+	lane := LaneFromDseqs("EcoRI", dSeq.Cut(&EcoRI), 100)
+
+	if len(lane.Fragments) != 2 || lane.Fragments[0].Size != 11 || lane.Fragments[1].Size != 15 {
+		t.Errorf("Expected fragments of 11 and 15 bp, got %v", lane.Fragments)
+	}
+
+	// Two sites in a plasmid give two fragments, the one across the origin
+	// first.
+	plasmid := strings.Repeat("A", 100) + "GAATTC" + strings.Repeat("A", 200) + "GAATTC" + strings.Repeat("A", 88)
+	dSeq = sequence.NewFromWatsonStrand(plasmid, constants.Circular)
+	lane = LaneFromDseqs("EcoRI", dSeq.Cut(&EcoRI), 100)
+	if len(lane.Fragments) != 2 || lane.Fragments[0].Size != 194 || lane.Fragments[1].Size != 206 {
+		t.Errorf("Expected fragments of 194 and 206 bp, got %v", lane.Fragments)
+	}
+}
+
+func TestBands(t *testing.T) {
+	gel := NewGel(1.0, NewLane("digest", []int{1000, 4000, 1010, 100}, 500))
+	gel.AddLadder(LambdaHindIIILadder)
+
+	bands := gel.Bands(gel.Lanes[0])
+	if len(bands) != 3 {
+		t.Fatalf("Expected 3 bands, got %v", bands)
+	}
+	if !reflect.DeepEqual(bands[1].Sizes, []int{1010, 1000}) {
+		t.Errorf("Expected 1010 and 1000 bp to co-migrate, got %v", bands[1].Sizes)
+	}
+	if math.Abs(bands[1].Mass-2010.0/6110*500) > 1e-9 {
+		t.Errorf("Expected the merged band to carry the mass of both fragments, got %f", bands[1].Mass)
+	}
+	if bands[0].Migration >= bands[1].Migration || bands[1].Migration >= bands[2].Migration {
+		t.Errorf("Expected bands ordered from the wells, got %v", bands)
+	}
+
+	ladder := gel.Lanes[1]
+	if !ladder.IsLadder || ladder.Name != "λ-HindIII" {
+		t.Errorf("Expected a λ-HindIII ladder lane, got %+v", ladder)
+	}
+
+	total := 0.0
+	for _, fragment := range LambdaHindIIILadder.Bands {
+		total += fragment.Mass
+	}
+	if math.Abs(total-DefaultLaneMass) > 1e-9 {
+		t.Errorf("Expected the λ-HindIII bands to add up to %f ng, got %f", DefaultLaneMass, total)
+	}
+
+	gel = NewGel(2.0)
+	gel.AddLadder(OneHundredBpLadder)
+	for _, band := range gel.Bands(gel.Lanes[0]) {
+		if len(band.Sizes) > 1 && !reflect.DeepEqual(band.Sizes, []int{517, 500}) {
+			t.Errorf("Expected only 517 and 500 bp to co-migrate, got %v", band.Sizes)
+		}
+	}
+}
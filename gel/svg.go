@@ -0,0 +1,99 @@
+package gel
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The dimensions of a rendered gel in pixels.
+const (
+	svgLabelWidth  = 60
+	svgLaneWidth   = 56
+	svgHeaderSize  = 40
+	svgGelHeight   = 400
+	svgFooterSize  = 16
+	svgBandHeight  = 4
+	svgBandPadding = 8
+)
+
+// Render the gel as an SVG image. Bands are drawn white on a dark gel with
+// an opacity proportional to their mass, relative to the heaviest band on
+// the gel. The sizes of the first ladder are labelled on the left.
+func (gel *Gel) WriteSVG(writer io.Writer) error {
+	bandsByLane := make([][]Band, len(gel.Lanes))
+	heaviest := 0.0
+	for i, lane := range gel.Lanes {
+		bandsByLane[i] = gel.Bands(lane)
+		for _, band := range bandsByLane[i] {
+			heaviest = math.Max(heaviest, band.Mass)
+		}
+	}
+
+	width := svgLabelWidth + len(gel.Lanes)*svgLaneWidth + svgBandPadding
+	height := svgHeaderSize + svgGelHeight + svgFooterSize
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&svg, `<rect x="0" y="0" width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+	fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="#1c1c24"/>`+"\n",
+		svgLabelWidth, svgHeaderSize, len(gel.Lanes)*svgLaneWidth, svgGelHeight)
+	fmt.Fprintf(&svg, `<text x="%d" y="%d" font-size="10">%s%% agarose</text>`+"\n",
+		svgLabelWidth, height-4, strconv.FormatFloat(gel.Percentage, 'f', -1, 64))
+
+	labelled := false
+	for i, lane := range gel.Lanes {
+		x := svgLabelWidth + i*svgLaneWidth
+		center := x + svgLaneWidth/2
+
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" font-size="11" text-anchor="middle">%s</text>`+"\n",
+			center, svgHeaderSize-16, html.EscapeString(lane.Name))
+		fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="#000000"/>`+"\n",
+			x+svgBandPadding, svgHeaderSize+4, svgLaneWidth-2*svgBandPadding, svgBandHeight+2)
+
+		for _, band := range bandsByLane[i] {
+			y := svgHeaderSize + band.Migration*svgGelHeight - svgBandHeight/2
+			opacity := 0.0
+			if heaviest > 0 {
+				opacity = math.Max(band.Mass/heaviest, 0.05)
+			}
+
+			fmt.Fprintf(&svg, `<rect x="%d" y="%.1f" width="%d" height="%d" fill="#ffffff" fill-opacity="%.3f">`,
+				x+svgBandPadding, y, svgLaneWidth-2*svgBandPadding, svgBandHeight, opacity)
+			fmt.Fprintf(&svg, `<title>%s bp, %.1f ng</title></rect>`+"\n", joinSizes(band.Sizes), band.Mass)
+
+			if lane.IsLadder && !labelled {
+				fmt.Fprintf(&svg, `<text x="%d" y="%.1f" font-size="9" text-anchor="end">%s</text>`+"\n",
+					svgLabelWidth-4, y+svgBandHeight, joinSizes(band.Sizes))
+			}
+		}
+		if lane.IsLadder {
+			labelled = true
+		}
+	}
+	svg.WriteString("</svg>\n")
+
+	_, err := io.WriteString(writer, svg.String())
+	return err
+}
+
+// Return the gel rendered as an SVG image.
+func (gel *Gel) SVG() (string, error) {
+	var svg strings.Builder
+	if err := gel.WriteSVG(&svg); err != nil {
+		return "", err
+	}
+	return svg.String(), nil
+}
+
+func joinSizes(sizes []int) string {
+	labels := make([]string, len(sizes))
+	for i, size := range sizes {
+		labels[i] = strconv.Itoa(size)
+	}
+	return strings.Join(labels, "/")
+}
@@ -0,0 +1,45 @@
+package gel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+	gel := NewGel(1.0)
+	gel.AddLadder(OneKbLadder)
+	gel.AddLane(NewLane("EcoRI & <BamHI>", []int{3000, 1000}, 500))
+
+	svg, err := gel.SVG()
+	if err != nil {
+		t.Fatalf("Error rendering the gel: %v", err)
+	}
+
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Errorf("Expected an svg element, got %s", svg)
+	}
+	if !strings.Contains(svg, "EcoRI &amp; &lt;BamHI&gt;") {
+		t.Errorf("Expected the lane name to be escaped")
+	}
+	if !strings.Contains(svg, "1% agarose") {
+		t.Errorf("Expected the gel percentage to be shown")
+	}
+
+	// Ten ladder bands and two digest bands.
+	if count := strings.Count(svg, "<title>"); count != 12 {
+		t.Errorf("Expected 12 bands, got %d", count)
+	}
+	if !strings.Contains(svg, "<title>3000 bp, 375.0 ng</title>") {
+		t.Errorf("Expected the 3000 bp band to carry 375 ng")
+	}
+
+	// The heaviest band is drawn opaque.
+	if !strings.Contains(svg, `fill-opacity="1.000"`) {
+		t.Errorf("Expected the heaviest band to be opaque")
+	}
+
+	// The ladder sizes are labelled once.
+	if count := strings.Count(svg, `text-anchor="end">10000<`); count != 1 {
+		t.Errorf("Expected the 10000 bp ladder band to be labelled once, got %d", count)
+	}
+}
@@ -6,3 +6,8 @@ require (
 	github.com/bebop/poly v0.31.1
 	github.com/secsy/goftp v0.0.0-20200609142545-aa2de14babf4
 )
+
+require (
+	github.com/lunny/log v0.0.0-20160921050905-7887c61bf0de // indirect
+	github.com/mitchellh/go-wordwrap v1.0.1 // indirect
+)
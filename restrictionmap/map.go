@@ -0,0 +1,141 @@
+/*
+Package restrictionmap draws restriction maps, the positions where the
+enzymes of a batch cut a sequence, as SVG images with the features annotated
+on the sequence or as text in the style of EMBOSS remap.
+*/
+package restrictionmap
+
+import (
+	"sort"
+
+	"github.com/bebop/poly/io/genbank"
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+	"github.com/rmcl/restriction-enzymes/seqio"
+	"github.com/rmcl/restriction-enzymes/sequence"
+)
+
+// Options for building a restriction map.
+type Options struct {
+	// Only show the enzymes that cut between MinCuts and MaxCuts times, e.g.
+	// a MaxCuts of 1 for unique cutters or a MinCuts and MaxCuts of 2 for
+	// double cutters. Zero is no limit.
+	MinCuts int
+	MaxCuts int
+
+	// The features to draw, e.g. from FeaturesFromGenbank. Optional.
+	Features []Feature
+}
+
+// A position where one or more enzymes cut the watson strand, between the
+// bases Position-1 and Position.
+type Cut struct {
+	Position int
+	Enzymes  []string
+}
+
+// An annotated region of a sequence.
+type Feature struct {
+	Name string
+
+	// The GenBank feature key, e.g. CDS or promoter.
+	Type string
+
+	// The first base of the feature and the base after its last. A feature
+	// of a circular sequence that spans the origin starts after it ends.
+	Start int
+	End   int
+
+	// True if the feature is on the crick strand.
+	Complement bool
+}
+
+// The cuts and features of a sequence.
+type Map struct {
+	Name     string
+	Length   int
+	Geometry constants.SequenceGeometry
+
+	// The cuts of the enzymes shown, ordered by position, and the number of
+	// times each enzyme cuts.
+	Cuts      []Cut
+	CutCounts map[string]int
+
+	Features []Feature
+}
+
+// Build the map of the cuts made by the batch in the watson strand of the
+// sequence.
+func New(name string, dseq *sequence.Dseq, batch *enzyme.RestrictionBatch, options Options) (*Map, error) {
+	isCircular := dseq.Geometry == constants.Circular
+	cutsByEnzyme, err := batch.Search(dseq.Watson, isCircular)
+	if err != nil {
+		return nil, err
+	}
+
+	restrictionMap := &Map{
+		Name:      name,
+		Length:    len(dseq.Watson),
+		Geometry:  dseq.Geometry,
+		Cuts:      []Cut{},
+		CutCounts: map[string]int{},
+		Features:  append([]Feature{}, options.Features...),
+	}
+
+	enzymesByPosition := map[int][]string{}
+	for enzymeName, cuts := range cutsByEnzyme {
+		positions := enzyme.NormalizeCuts(cuts, len(dseq.Watson), isCircular)
+		if len(positions) == 0 ||
+			(options.MinCuts > 0 && len(positions) < options.MinCuts) ||
+			(options.MaxCuts > 0 && len(positions) > options.MaxCuts) {
+			continue
+		}
+
+		restrictionMap.CutCounts[enzymeName] = len(positions)
+		for _, position := range positions {
+			enzymesByPosition[position] = append(enzymesByPosition[position], enzymeName)
+		}
+	}
+
+	for position, enzymes := range enzymesByPosition {
+		sort.Strings(enzymes)
+		restrictionMap.Cuts = append(restrictionMap.Cuts, Cut{Position: position, Enzymes: enzymes})
+	}
+	sort.Slice(restrictionMap.Cuts, func(i, j int) bool {
+		return restrictionMap.Cuts[i].Position < restrictionMap.Cuts[j].Position
+	})
+	return restrictionMap, nil
+}
+
+// The qualifiers used to name a GenBank feature, in order of preference.
+var featureNameQualifiers = []string{"label", "gene", "product", "note"}
+
+// Return the features of a GenBank record to draw on its map. The source
+// feature, which spans the whole record, is left out. Features are named by
+// their label, gene or product qualifier, or else their type.
+func FeaturesFromGenbank(record genbank.Genbank) []Feature {
+	features := []Feature{}
+	for _, feature := range record.Features {
+		if feature.Type == "source" {
+			continue
+		}
+
+		name := feature.Type
+		for _, qualifier := range featureNameQualifiers {
+			if value := feature.Attributes[qualifier]; value != "" {
+				name = value
+				break
+			}
+		}
+
+		start, end := seqio.LocationSpan(feature.Location)
+		features = append(features, Feature{
+			Name:       name,
+			Type:       feature.Type,
+			Start:      start,
+			End:        end,
+			Complement: seqio.IsComplement(feature.Location),
+		})
+	}
+	return features
+}
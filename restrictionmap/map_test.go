@@ -0,0 +1,96 @@
+package restrictionmap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bebop/poly/io/genbank"
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/db"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+	"github.com/rmcl/restriction-enzymes/sequence"
+)
+
+// A 60 bp plasmid with one EcoRI and two BamHI sites, one of them across
+// the origin.
+const testRecord = `LOCUS       pTest                     60 bp    DNA     circular SYN 01-JAN-2024
+DEFINITION  Test plasmid.
+FEATURES             Location/Qualifiers
+     source          1..60
+                     /organism="synthetic DNA construct"
+     promoter        5..20
+                     /label="pLac"
+     CDS             complement(25..45)
+                     /gene="bla"
+     misc_feature    join(55..60,1..3)
+                     /note="origin spanning"
+     CDS             complement(join(30..35,40..50))
+                     /product="split"
+ORIGIN
+        1 ccaattcgaa ttcaaaaaaa aaaaaaaaaa ggatccaaaa aaaaaaaaaa aaaaaaggat
+//
+`
+
+func testBatch() enzyme.RestrictionBatch {
+	return enzyme.NewRestrictionBatch(db.Enzymes["EcoRI"], db.Enzymes["BamHI"], db.Enzymes["HindIII"])
+}
+
+func TestNew(t *testing.T) {
+	record, err := genbank.Parse(strings.NewReader(testRecord))
+	if err != nil {
+		t.Fatalf("Failed to parse the record: %s", err)
+	}
+	dseq := sequence.NewFromWatsonStrand(strings.ToUpper(record.Sequence), constants.Circular)
+	batch := testBatch()
+
+	restrictionMap, err := New("pTest", dseq, &batch, Options{})
+	if err != nil {
+		t.Fatalf("Failed to build the map: %s", err)
+	}
+	if restrictionMap.Length != 60 || restrictionMap.Geometry != constants.Circular {
+		t.Errorf("Expected a 60 bp circular map, got %d bp %s", restrictionMap.Length, restrictionMap.Geometry)
+	}
+
+	expected := []Cut{{8, []string{"EcoRI"}}, {31, []string{"BamHI"}}, {57, []string{"BamHI"}}}
+	if !reflect.DeepEqual(restrictionMap.Cuts, expected) {
+		t.Errorf("Expected cuts %v, got %v", expected, restrictionMap.Cuts)
+	}
+	if !reflect.DeepEqual(restrictionMap.CutCounts, map[string]int{"BamHI": 2, "EcoRI": 1}) {
+		t.Errorf("Expected BamHI to cut twice and EcoRI once, got %v", restrictionMap.CutCounts)
+	}
+
+	unique, _ := New("pTest", dseq, &batch, Options{MaxCuts: 1})
+	if !reflect.DeepEqual(unique.Cuts, []Cut{{8, []string{"EcoRI"}}}) {
+		t.Errorf("Expected only the EcoRI cut, got %v", unique.Cuts)
+	}
+
+	double, _ := New("pTest", dseq, &batch, Options{MinCuts: 2, MaxCuts: 2})
+	if len(double.Cuts) != 2 || double.Cuts[0].Enzymes[0] != "BamHI" || double.Cuts[1].Position != 57 || double.CutCounts["EcoRI"] != 0 {
+		t.Errorf("Expected only the BamHI cuts, got %v", double.Cuts)
+	}
+
+	// The site across the origin is not found in the linear sequence.
+	dseq.Geometry = constants.Linear
+	linear, _ := New("pTest", dseq, &batch, Options{})
+	if len(linear.Cuts) != 2 || linear.CutCounts["BamHI"] != 1 {
+		t.Errorf("Expected one BamHI and one EcoRI cut in the linear sequence, got %v", linear.Cuts)
+	}
+}
+
+func TestFeaturesFromGenbank(t *testing.T) {
+	record, err := genbank.Parse(strings.NewReader(testRecord))
+	if err != nil {
+		t.Fatalf("Failed to parse the record: %s", err)
+	}
+
+	expected := []Feature{
+		{Name: "pLac", Type: "promoter", Start: 4, End: 20},
+		{Name: "bla", Type: "CDS", Start: 24, End: 45, Complement: true},
+		{Name: "origin spanning", Type: "misc_feature", Start: 54, End: 3},
+		{Name: "split", Type: "CDS", Start: 29, End: 50, Complement: true},
+	}
+	if features := FeaturesFromGenbank(record); !reflect.DeepEqual(features, expected) {
+		t.Errorf("Expected features %v, got %v", expected, features)
+	}
+}
@@ -0,0 +1,446 @@
+package restrictionmap
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+)
+
+// The dimensions of a rendered map in pixels.
+const (
+	svgCircularSize    = 640
+	svgCircularRadius  = 180
+	svgLinearWidth     = 900
+	svgMargin          = 40
+	svgLabelSpacing    = 13
+	svgCharWidth       = 6.2
+	svgFeatureWidth    = 10
+	svgFeatureSpacing  = 16
+	svgArrowLength     = 8
+	svgCutTickLength   = 10
+	svgCutLabelOffset  = 36
+	svgLinearRowHeight = 30
+)
+
+// The fill colour of the features of each GenBank feature key.
+var featureColors = map[string]string{
+	"CDS":          "#f2a541",
+	"gene":         "#f2c14e",
+	"promoter":     "#5b8e7d",
+	"terminator":   "#bc4b51",
+	"rep_origin":   "#8cb369",
+	"primer_bind":  "#9a9a9a",
+	"misc_feature": "#7e8cc4",
+}
+
+const defaultFeatureColor = "#7b9acc"
+
+func featureColor(feature Feature) string {
+	if color, ok := featureColors[feature.Type]; ok {
+		return color
+	}
+	return defaultFeatureColor
+}
+
+// Render the map as an SVG image, a plasmid map for a circular sequence and
+// a ruler for a linear one. Cuts are labelled with their enzymes and
+// position, in bold if every enzyme cuts once. Labels are spread out so they
+// do not overlap and features are stacked in tracks.
+func (restrictionMap *Map) WriteSVG(writer io.Writer) error {
+	var svg strings.Builder
+	if restrictionMap.Geometry == constants.Circular {
+		restrictionMap.writeCircularSVG(&svg)
+	} else {
+		restrictionMap.writeLinearSVG(&svg)
+	}
+
+	_, err := io.WriteString(writer, svg.String())
+	return err
+}
+
+// Return the map rendered as an SVG image.
+func (restrictionMap *Map) SVG() (string, error) {
+	var svg strings.Builder
+	if err := restrictionMap.WriteSVG(&svg); err != nil {
+		return "", err
+	}
+	return svg.String(), nil
+}
+
+// A label and the position it is drawn at.
+type label struct {
+	text string
+	bold bool
+
+	// The angle of the cut on a circular map or its x on a linear one.
+	anchor float64
+	x      float64
+	y      float64
+}
+
+func (restrictionMap *Map) cutLabel(cut Cut) label {
+	bold := true
+	for _, name := range cut.Enzymes {
+		bold = bold && restrictionMap.CutCounts[name] == 1
+	}
+	return label{text: fmt.Sprintf("%s (%d)", strings.Join(cut.Enzymes, ", "), cut.Position), bold: bold}
+}
+
+func labelWidth(text string) float64 {
+	return float64(len([]rune(text))) * svgCharWidth
+}
+
+func writeHeader(svg *strings.Builder, width int, height int) {
+	fmt.Fprintf(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(svg, `<rect x="0" y="0" width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+}
+
+func writeLabel(svg *strings.Builder, text label, textAnchor string) {
+	weight := ""
+	if text.bold {
+		weight = ` font-weight="bold"`
+	}
+	fmt.Fprintf(svg, `<text x="%.1f" y="%.1f" font-size="10" text-anchor="%s"%s>%s</text>`+"\n",
+		text.x, text.y, textAnchor, weight, html.EscapeString(text.text))
+}
+
+// Move the sorted positions apart so that neighbours are at least spacing
+// apart, keeping them between low and high where there is room, and as
+// close to where they were as possible.
+func spreadLabels(positions []float64, spacing float64, low float64, high float64) []float64 {
+	spread := append([]float64{}, positions...)
+	for i := range spread {
+		if i > 0 {
+			spread[i] = math.Max(spread[i], spread[i-1]+spacing)
+		}
+		spread[i] = math.Max(spread[i], low)
+	}
+	for i := len(spread) - 1; i >= 0; i-- {
+		limit := high
+		if i < len(spread)-1 {
+			limit = spread[i+1] - spacing
+		}
+		spread[i] = math.Min(spread[i], limit)
+	}
+	return spread
+}
+
+// Assign each interval, sorted by start, to the first track where it does
+// not overlap another interval. Intervals of a circular map of the given
+// length also overlap across the origin. Return the track of each interval
+// and the number of tracks.
+func packTracks(intervals [][2]float64, circularLength float64) ([]int, int) {
+	tracks := [][][2]float64{}
+	assigned := make([]int, len(intervals))
+	overlaps := func(a [2]float64, b [2]float64) bool {
+		shifts := []float64{0}
+		if circularLength > 0 {
+			shifts = []float64{-circularLength, 0, circularLength}
+		}
+		for _, shift := range shifts {
+			if a[0] < b[1]+shift && b[0]+shift < a[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, interval := range intervals {
+		track := 0
+		for ; track < len(tracks); track++ {
+			free := true
+			for _, other := range tracks[track] {
+				if overlaps(interval, other) {
+					free = false
+					break
+				}
+			}
+			if free {
+				break
+			}
+		}
+		if track == len(tracks) {
+			tracks = append(tracks, nil)
+		}
+		tracks[track] = append(tracks[track], interval)
+		assigned[i] = track
+	}
+	return assigned, len(tracks)
+}
+
+// Return the features ordered by start with the end of those that span the
+// origin moved past the length of the sequence.
+func (restrictionMap *Map) unwrappedFeatures() []Feature {
+	features := []Feature{}
+	for _, feature := range restrictionMap.Features {
+		if feature.Start > feature.End {
+			feature.End += restrictionMap.Length
+		}
+		if feature.End > feature.Start {
+			features = append(features, feature)
+		}
+	}
+	sort.SliceStable(features, func(i, j int) bool {
+		return features[i].Start < features[j].Start
+	})
+	return features
+}
+
+func (restrictionMap *Map) writeCircularSVG(svg *strings.Builder) {
+	features := restrictionMap.unwrappedFeatures()
+	intervals := make([][2]float64, len(features))
+	for i, feature := range features {
+		intervals[i] = [2]float64{float64(feature.Start), float64(feature.End)}
+	}
+	featureTracks, _ := packTracks(intervals, float64(restrictionMap.Length))
+
+	// Split the cut labels between the right and left halves of the map.
+	left, right := []label{}, []label{}
+	for _, cut := range restrictionMap.Cuts {
+		text := restrictionMap.cutLabel(cut)
+		text.anchor = restrictionMap.angle(cut.Position)
+		text.y = -(svgCircularRadius + svgCutLabelOffset) * math.Cos(text.anchor)
+		if math.Sin(text.anchor) >= 0 {
+			right = append(right, text)
+		} else {
+			left = append(left, text)
+		}
+	}
+
+	// Grow the map if a half has more labels than fit beside the circle.
+	height := float64(svgCircularSize)
+	for _, side := range [][]label{left, right} {
+		height = math.Max(height, float64(len(side)+1)*svgLabelSpacing+2*svgMargin)
+	}
+	width := float64(svgCircularSize)
+	center := [2]float64{width / 2, height / 2}
+
+	writeHeader(svg, int(width), int(height))
+	fmt.Fprintf(svg, `<circle cx="%.1f" cy="%.1f" r="%d" fill="none" stroke="#404040" stroke-width="2"/>`+"\n",
+		center[0], center[1], svgCircularRadius)
+	fmt.Fprintf(svg, `<text x="%.1f" y="%.1f" font-size="16" font-weight="bold" text-anchor="middle">%s</text>`+"\n",
+		center[0], center[1]-4, html.EscapeString(restrictionMap.Name))
+	fmt.Fprintf(svg, `<text x="%.1f" y="%.1f" font-size="12" text-anchor="middle">%d bp</text>`+"\n",
+		center[0], center[1]+14, restrictionMap.Length)
+
+	for i, feature := range features {
+		radius := float64(svgCircularRadius) - svgFeatureSpacing*float64(featureTracks[i]+1)
+		restrictionMap.writeFeatureArc(svg, center, radius, feature)
+	}
+
+	for _, side := range []struct {
+		labels []label
+		sign   float64
+	}{{right, 1}, {left, -1}} {
+		sort.SliceStable(side.labels, func(i, j int) bool {
+			return side.labels[i].y < side.labels[j].y
+		})
+		ys := make([]float64, len(side.labels))
+		for i := range side.labels {
+			ys[i] = side.labels[i].y
+		}
+		ys = spreadLabels(ys, svgLabelSpacing, svgMargin-height/2, height/2-svgMargin)
+
+		textAnchor := "start"
+		if side.sign < 0 {
+			textAnchor = "end"
+		}
+		labelRadius := float64(svgCircularRadius + svgCutLabelOffset)
+		for i := range side.labels {
+			text := &side.labels[i]
+			offset := math.Sqrt(math.Max(labelRadius*labelRadius-ys[i]*ys[i], 0))
+			text.x = center[0] + side.sign*(offset+4)
+			text.y = center[1] + ys[i]
+
+			// A tick on the circle and a leader line to the label.
+			sin, cos := math.Sin(text.anchor), math.Cos(text.anchor)
+			tickRadius := float64(svgCircularRadius + svgCutTickLength)
+			fmt.Fprintf(svg, `<path d="M %.1f %.1f L %.1f %.1f L %.1f %.1f" fill="none" stroke="#606060" stroke-width="1"/>`+"\n",
+				center[0]+svgCircularRadius*sin, center[1]-svgCircularRadius*cos,
+				center[0]+tickRadius*sin, center[1]-tickRadius*cos,
+				text.x-side.sign*2, text.y-3)
+			writeLabel(svg, *text, textAnchor)
+		}
+	}
+	svg.WriteString("</svg>\n")
+}
+
+// Return the angle of a position clockwise from the top of a circular map.
+func (restrictionMap *Map) angle(position int) float64 {
+	if restrictionMap.Length == 0 {
+		return 0
+	}
+	return 2 * math.Pi * float64(position) / float64(restrictionMap.Length)
+}
+
+// Draw a feature of a circular map as an arrow along the circle of the
+// radius, pointing to its 3' end.
+func (restrictionMap *Map) writeFeatureArc(svg *strings.Builder, center [2]float64, radius float64, feature Feature) {
+	start, end := restrictionMap.angle(feature.Start), restrictionMap.angle(feature.End)
+	head := math.Min(svgArrowLength/radius, (end-start)/2)
+	outer, inner := radius+svgFeatureWidth/2, radius-svgFeatureWidth/2
+
+	point := func(r float64, angle float64) string {
+		return fmt.Sprintf("%.1f %.1f", center[0]+r*math.Sin(angle), center[1]-r*math.Cos(angle))
+	}
+	largeArc := func(from float64, to float64) int {
+		if math.Abs(to-from) > math.Pi {
+			return 1
+		}
+		return 0
+	}
+
+	var path string
+	if feature.Complement {
+		body := start + head
+		path = fmt.Sprintf("M %s L %s A %.1f %.1f 0 %d 1 %s L %s A %.1f %.1f 0 %d 0 %s Z",
+			point(radius, start), point(outer, body),
+			outer, outer, largeArc(body, end), point(outer, end),
+			point(inner, end),
+			inner, inner, largeArc(body, end), point(inner, body))
+	} else {
+		body := end - head
+		path = fmt.Sprintf("M %s A %.1f %.1f 0 %d 1 %s L %s L %s A %.1f %.1f 0 %d 0 %s Z",
+			point(outer, start),
+			outer, outer, largeArc(start, body), point(outer, body),
+			point(radius, end), point(inner, body),
+			inner, inner, largeArc(start, body), point(inner, start))
+	}
+
+	fmt.Fprintf(svg, `<path d="%s" fill="%s" stroke="#303030" stroke-width="0.5"><title>%s</title></path>`+"\n",
+		path, featureColor(feature), html.EscapeString(featureTitle(feature, restrictionMap.Length)))
+
+	middle := (start + end) / 2
+	textAnchor := "start"
+	if math.Sin(middle) > 0 {
+		textAnchor = "end"
+	}
+	labelRadius := inner - 6
+	writeLabel(svg, label{
+		text: feature.Name,
+		x:    center[0] + labelRadius*math.Sin(middle),
+		y:    center[1] - labelRadius*math.Cos(middle) + 3,
+	}, textAnchor)
+}
+
+// Return a description of the feature with its 1-based position.
+func featureTitle(feature Feature, length int) string {
+	end := feature.End
+	if length > 0 && end > length {
+		end -= length
+	}
+	strand := "+"
+	if feature.Complement {
+		strand = "-"
+	}
+	return fmt.Sprintf("%s (%s) %d..%d %s", feature.Name, feature.Type, feature.Start+1, end, strand)
+}
+
+// Return the interval between ruler ticks that gives about ten ticks.
+func tickInterval(length int) int {
+	interval := 1
+	for {
+		for _, step := range []int{1, 2, 5} {
+			if length/(interval*step) <= 10 {
+				return interval * step
+			}
+		}
+		interval *= 10
+	}
+}
+
+func (restrictionMap *Map) writeLinearSVG(svg *strings.Builder) {
+	width := float64(svgLinearWidth)
+	scale := 0.0
+	if restrictionMap.Length > 0 {
+		scale = (width - 2*svgMargin) / float64(restrictionMap.Length)
+	}
+	x := func(position int) float64 {
+		return svgMargin + float64(position)*scale
+	}
+
+	// Stack the cut labels in rows above the ruler.
+	labels := make([]label, len(restrictionMap.Cuts))
+	labelExtents := make([][2]float64, len(restrictionMap.Cuts))
+	for i, cut := range restrictionMap.Cuts {
+		labels[i] = restrictionMap.cutLabel(cut)
+		labels[i].anchor = x(cut.Position)
+		half := labelWidth(labels[i].text) / 2
+		labels[i].x = math.Min(math.Max(labels[i].anchor, half+2), width-half-2)
+		labelExtents[i] = [2]float64{labels[i].x - half - 3, labels[i].x + half + 3}
+	}
+	labelRows, rowCount := packTracks(labelExtents, 0)
+
+	// Stack the features, with their names below them, in tracks under the
+	// ruler.
+	features := restrictionMap.unwrappedFeatures()
+	featureExtents := make([][2]float64, len(features))
+	for i, feature := range features {
+		start, end := x(feature.Start), x(min(feature.End, restrictionMap.Length))
+		half := labelWidth(feature.Name) / 2
+		middle := (start + end) / 2
+		featureExtents[i] = [2]float64{math.Min(start, middle-half) - 3, math.Max(end, middle+half) + 3}
+	}
+	featureTracks, trackCount := packTracks(featureExtents, 0)
+
+	rulerY := svgMargin + float64(rowCount)*svgLabelSpacing + 2*svgCutTickLength
+	height := rulerY + 28 + float64(trackCount)*svgLinearRowHeight + svgMargin/2
+
+	writeHeader(svg, int(width), int(height))
+	fmt.Fprintf(svg, `<text x="%d" y="%d" font-size="16" font-weight="bold">%s</text>`+"\n",
+		svgMargin, 24, html.EscapeString(restrictionMap.Name))
+	fmt.Fprintf(svg, `<text x="%.1f" y="%d" font-size="12" text-anchor="end">%d bp</text>`+"\n",
+		width-svgMargin, 24, restrictionMap.Length)
+	fmt.Fprintf(svg, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#404040" stroke-width="2"/>`+"\n",
+		x(0), rulerY, x(restrictionMap.Length), rulerY)
+
+	interval := tickInterval(restrictionMap.Length)
+	for position := 0; position <= restrictionMap.Length; position += interval {
+		fmt.Fprintf(svg, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#404040" stroke-width="1"/>`+"\n",
+			x(position), rulerY, x(position), rulerY+5)
+		fmt.Fprintf(svg, `<text x="%.1f" y="%.1f" font-size="9" text-anchor="middle" fill="#606060">%d</text>`+"\n",
+			x(position), rulerY+15, position)
+	}
+
+	for i := range labels {
+		text := &labels[i]
+		text.y = rulerY - 2*svgCutTickLength - float64(labelRows[i])*svgLabelSpacing
+		fmt.Fprintf(svg, `<path d="M %.1f %.1f L %.1f %.1f L %.1f %.1f" fill="none" stroke="#606060" stroke-width="1"/>`+"\n",
+			text.anchor, rulerY, text.anchor, rulerY-svgCutTickLength, text.x, text.y+2)
+		writeLabel(svg, *text, "middle")
+	}
+
+	for i, feature := range features {
+		top := rulerY + 28 + float64(featureTracks[i])*svgLinearRowHeight
+		restrictionMap.writeFeatureArrow(svg, x, top, feature)
+	}
+	svg.WriteString("</svg>\n")
+}
+
+// Draw a feature of a linear map as an arrow pointing to its 3' end with
+// its name below it. A feature that spans the origin is cut at the end of
+// the ruler.
+func (restrictionMap *Map) writeFeatureArrow(svg *strings.Builder, x func(int) float64, top float64, feature Feature) {
+	start, end := x(feature.Start), x(min(feature.End, restrictionMap.Length))
+	head := math.Min(svgArrowLength, (end-start)/2)
+	bottom, middle := top+svgFeatureWidth, top+svgFeatureWidth/2
+
+	var points string
+	if feature.Complement {
+		points = fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f",
+			start, middle, start+head, top, end, top, end, bottom, start+head, bottom)
+	} else {
+		points = fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f",
+			start, top, end-head, top, end, middle, end-head, bottom, start, bottom)
+	}
+
+	fmt.Fprintf(svg, `<polygon points="%s" fill="%s" stroke="#303030" stroke-width="0.5"><title>%s</title></polygon>`+"\n",
+		points, featureColor(feature), html.EscapeString(featureTitle(feature, restrictionMap.Length)))
+	writeLabel(svg, label{text: feature.Name, x: (start + end) / 2, y: bottom + 11}, "middle")
+}
@@ -0,0 +1,106 @@
+package restrictionmap
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bebop/poly/io/genbank"
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/sequence"
+)
+
+func testMap(t *testing.T, geometry constants.SequenceGeometry) *Map {
+	record, err := genbank.Parse(strings.NewReader(testRecord))
+	if err != nil {
+		t.Fatalf("Failed to parse the record: %s", err)
+	}
+
+	batch := testBatch()
+	dseq := sequence.NewFromWatsonStrand(strings.ToUpper(record.Sequence), geometry)
+	restrictionMap, err := New("pTest", dseq, &batch, Options{Features: FeaturesFromGenbank(record)})
+	if err != nil {
+		t.Fatalf("Failed to build the map: %s", err)
+	}
+	return restrictionMap
+}
+
+func renderSVG(t *testing.T, restrictionMap *Map) string {
+	t.Helper()
+	svg, err := restrictionMap.SVG()
+	if err != nil {
+		t.Fatalf("Error rendering the map: %v", err)
+	}
+	return svg
+}
+
+func TestWriteSVG(t *testing.T) {
+	for _, geometry := range []constants.SequenceGeometry{constants.Circular, constants.Linear} {
+		svg := renderSVG(t, testMap(t, geometry))
+
+		if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>\n") {
+			t.Errorf("Expected an svg element for the %s map, got %s", geometry, svg)
+		}
+		if !strings.Contains(svg, `font-weight="bold">EcoRI (8)</text>`) {
+			t.Errorf("Expected the unique EcoRI cut in bold on the %s map", geometry)
+		}
+		if !strings.Contains(svg, ">BamHI (31)</text>") {
+			t.Errorf("Expected the BamHI cut on the %s map", geometry)
+		}
+		for _, name := range []string{"pLac", "bla", "split"} {
+			if !strings.Contains(svg, ">"+name+"</text>") {
+				t.Errorf("Expected the %s feature on the %s map", name, geometry)
+			}
+		}
+		if !strings.Contains(svg, "<title>bla (CDS) 25..45 -</title>") {
+			t.Errorf("Expected the position of bla in its title on the %s map", geometry)
+		}
+	}
+
+	circular := renderSVG(t, testMap(t, constants.Circular))
+	if !strings.Contains(circular, "<circle") || !strings.Contains(circular, "<title>origin spanning (misc_feature) 55..3 +</title>") {
+		t.Errorf("Expected a circle and the feature across the origin on the circular map")
+	}
+	if linear := renderSVG(t, testMap(t, constants.Linear)); strings.Contains(linear, "<circle") || !strings.Contains(linear, "<polygon") {
+		t.Errorf("Expected a ruler with feature arrows on the linear map")
+	}
+}
+
+func TestSpreadLabels(t *testing.T) {
+	spread := spreadLabels([]float64{0, 1, 2, 50, 100}, 10, -5, 100)
+	for i := 1; i < len(spread); i++ {
+		if spread[i]-spread[i-1] < 10-1e-9 {
+			t.Errorf("Expected labels at least 10 apart, got %v", spread)
+		}
+	}
+	if spread[0] < -5 || spread[len(spread)-1] > 100 || spread[3] != 50 {
+		t.Errorf("Expected labels within the bounds that keep their place when there is room, got %v", spread)
+	}
+
+	// Labels crowded at the end are pushed back from it.
+	if spread := spreadLabels([]float64{99, 100}, 10, 0, 100); math.Abs(spread[0]-90) > 1e-9 {
+		t.Errorf("Expected the first label to move to 90, got %v", spread)
+	}
+}
+
+func TestPackTracks(t *testing.T) {
+	tracks, count := packTracks([][2]float64{{0, 10}, {5, 15}, {12, 20}, {16, 30}}, 0)
+	if !reflect.DeepEqual(tracks, []int{0, 1, 0, 1}) || count != 2 {
+		t.Errorf("Expected two alternating tracks, got %v", tracks)
+	}
+
+	// An interval across the origin of a circular map overlaps the first.
+	tracks, _ = packTracks([][2]float64{{0, 10}, {50, 65}}, 60)
+	if !reflect.DeepEqual(tracks, []int{0, 1}) {
+		t.Errorf("Expected the interval across the origin on a second track, got %v", tracks)
+	}
+}
+
+func TestTickInterval(t *testing.T) {
+	for length, expected := range map[int]int{60: 10, 3000: 500, 4361: 500, 48502: 5000} {
+		if interval := tickInterval(length); interval != expected {
+			t.Errorf("Expected ticks every %d for %d bp, got %d", expected, length, interval)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package restrictionmap
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bebop/poly/transform"
+	"github.com/rmcl/restriction-enzymes/codon"
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+	"github.com/rmcl/restriction-enzymes/sequence"
+)
+
+// The number of bases written per line when none is given.
+const DefaultTextWidth = 60
+
+// Options for writing a text restriction map.
+type TextOptions struct {
+	// The number of bases per line. Defaults to DefaultTextWidth.
+	Width int
+
+	// Write the translation of the six reading frames under each line.
+	Translate bool
+
+	// Only mark the enzymes whose sites are found between MinCuts and
+	// MaxCuts times. Zero is no limit. The other enzymes that cut are listed
+	// in the summary as excluded.
+	MinCuts int
+	MaxCuts int
+}
+
+// The cuts of an enzyme found in a sequence.
+type enzymeCuts struct {
+	enzyme *enzyme.Enzyme
+	sites  int
+
+	// The watson and crick strand cut positions.
+	watson []int
+	crick  []int
+}
+
+// A name written above the sequence and the column of the cut it labels.
+type textLabel struct {
+	column int
+	name   string
+	row    int
+}
+
+/*
+Write a restriction map of the sequence in the style of EMBOSS remap. The
+sequence is written in blocks of Width bases with its complement below it.
+Each enzyme's name is written above its cut in the watson strand, which is
+marked with \ above the base before the cut. The cut in the crick strand is
+marked with / below the complement, so the staggered cuts of enzymes that
+cut outside their site, e.g. Type IIS enzymes, are shown apart.
+
+The map ends with a summary of the enzymes that cut, with the number of
+sites found and their watson strand cut positions, and the enzymes that do
+not cut.
+*/
+func WriteText(writer io.Writer, name string, dseq *sequence.Dseq, batch *enzyme.RestrictionBatch, options TextOptions) error {
+	width := options.Width
+	if width <= 0 {
+		width = DefaultTextWidth
+	}
+
+	watson := strings.ToUpper(dseq.Watson)
+	complement := transform.Complement(watson)
+	isCircular := dseq.Geometry == constants.Circular
+
+	cutters, excluded, nonCutters := findEnzymeCuts(watson, isCircular, batch, options)
+
+	// The names and markers of each base, indexed by the base before the
+	// cut.
+	namesByBase := map[int][]string{}
+	watsonMarkers, crickMarkers := map[int]bool{}, map[int]bool{}
+	for _, cuts := range cutters {
+		for _, cut := range cuts.watson {
+			base := markerBase(cut, len(watson), isCircular)
+			if base >= 0 {
+				namesByBase[base] = append(namesByBase[base], cuts.enzyme.Name)
+				watsonMarkers[base] = true
+			}
+		}
+		for _, cut := range cuts.crick {
+			if base := markerBase(cut, len(watson), isCircular); base >= 0 {
+				crickMarkers[base] = true
+			}
+		}
+	}
+
+	var frames [6]string
+	if options.Translate {
+		frames = sixFrames(watson)
+	}
+
+	margin := len(fmt.Sprint(len(watson))) + 1
+	pad := strings.Repeat(" ", margin+1)
+
+	var text strings.Builder
+	geometry := "linear"
+	if isCircular {
+		geometry = "circular"
+	}
+	fmt.Fprintf(&text, "# %s, %d bp, %s\n\n", name, len(watson), geometry)
+
+	for start := 0; start < len(watson); start += width {
+		end := min(start+width, len(watson))
+
+		labels := []textLabel{}
+		for base := start; base < end; base++ {
+			names := append([]string{}, namesByBase[base]...)
+			sort.Strings(names)
+			for _, enzymeName := range names {
+				labels = append(labels, textLabel{column: base - start, name: enzymeName})
+			}
+		}
+		rows := placeLabels(labels)
+		for row := rows; row >= 1; row-- {
+			line := []byte{}
+			put := func(column int, value string) {
+				for len(line) < column+len(value) {
+					line = append(line, ' ')
+				}
+				copy(line[column:], value)
+			}
+			for _, label := range labels {
+				if label.row > row {
+					put(label.column, "|")
+				}
+			}
+			for _, label := range labels {
+				if label.row == row {
+					put(label.column, label.name)
+				}
+			}
+			text.WriteString(strings.TrimRight(pad+string(line), " ") + "\n")
+		}
+
+		text.WriteString(markerLine(pad, start, end, watsonMarkers, '\\'))
+		fmt.Fprintf(&text, "%*d %s %d\n", margin, start+1, watson[start:end], end)
+		text.WriteString(pad + ruler(start, end) + "\n")
+		text.WriteString(pad + complement[start:end] + "\n")
+		text.WriteString(markerLine(pad, start, end, crickMarkers, '/'))
+
+		if options.Translate {
+			for i, frameName := range []string{"F1", "F2", "F3", "R1", "R2", "R3"} {
+				if aminoAcids := strings.TrimRight(frames[i][start:end], " "); aminoAcids != "" {
+					fmt.Fprintf(&text, "%*s %s\n", margin, frameName, aminoAcids)
+				}
+			}
+		}
+		text.WriteString("\n")
+	}
+
+	writeSummary(&text, cutters, excluded, nonCutters)
+
+	_, err := io.WriteString(writer, text.String())
+	return err
+}
+
+// Find the sites of every enzyme in the batch. Return the enzymes that cut,
+// ordered by name, split into those within the cut limits of the options and
+// those excluded by them, and the names of the enzymes that do not cut.
+func findEnzymeCuts(
+	watson string,
+	isCircular bool,
+	batch *enzyme.RestrictionBatch,
+	options TextOptions,
+) ([]enzymeCuts, []enzymeCuts, []string) {
+	cutsByEnzyme := map[string]*enzymeCuts{}
+	for _, result := range batch.FindAll(watson, isCircular) {
+		cuts, ok := cutsByEnzyme[result.Enzyme.Name]
+		if !ok {
+			cuts = &enzymeCuts{enzyme: result.Enzyme}
+			cutsByEnzyme[result.Enzyme.Name] = cuts
+		}
+		cuts.sites++
+		cuts.watson = append(cuts.watson, result.WatsonCutIndexes()...)
+		cuts.crick = append(cuts.crick, result.CrickCutIndex)
+		if result.HasSecondCut {
+			cuts.crick = append(cuts.crick, result.CrickCutIndex2)
+		}
+	}
+
+	cutters, excluded, nonCutters := []enzymeCuts{}, []enzymeCuts{}, []string{}
+	for _, ez := range batch.Enzymes {
+		cuts, ok := cutsByEnzyme[ez.Name]
+		if !ok {
+			nonCutters = append(nonCutters, ez.Name)
+			continue
+		}
+		delete(cutsByEnzyme, ez.Name)
+
+		cuts.watson = enzyme.NormalizeCuts(cuts.watson, len(watson), isCircular)
+		cuts.crick = enzyme.NormalizeCuts(cuts.crick, len(watson), isCircular)
+		if (options.MinCuts > 0 && cuts.sites < options.MinCuts) ||
+			(options.MaxCuts > 0 && cuts.sites > options.MaxCuts) {
+			excluded = append(excluded, *cuts)
+		} else {
+			cutters = append(cutters, *cuts)
+		}
+	}
+
+	sort.Strings(nonCutters)
+	for _, list := range [][]enzymeCuts{cutters, excluded} {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].enzyme.Name < list[j].enzyme.Name
+		})
+	}
+	return cutters, excluded, nonCutters
+}
+
+// Return the base before a cut, where its marker is written, or -1 if the
+// cut is at an end of a linear sequence.
+func markerBase(cut int, length int, isCircular bool) int {
+	if isCircular {
+		return ((cut-1)%length + length) % length
+	}
+	if cut <= 0 || cut >= length {
+		return -1
+	}
+	return cut - 1
+}
+
+// Assign each label to the lowest row above the sequence where neither it
+// nor the line down from it to its cut crosses another label. Labels are
+// placed from the right so that the line down from a label only passes the
+// labels to its right, which it never crosses. Return the number of rows.
+func placeLabels(labels []textLabel) int {
+	rows := 0
+	placed := []textLabel{}
+	for i := len(labels) - 1; i >= 0; i-- {
+		label := &labels[i]
+		for row := 1; ; row++ {
+			if labelFits(placed, label.column, label.name, row) {
+				label.row = row
+				rows = max(rows, row)
+				break
+			}
+		}
+		placed = append(placed, *label)
+	}
+	return rows
+}
+
+func labelFits(placed []textLabel, column int, name string, row int) bool {
+	end := column + len(name)
+	for _, other := range placed {
+		otherEnd := other.column + len(other.name)
+		switch {
+		case other.row == row && column <= otherEnd && other.column <= end:
+			// The labels overlap, or touch without a space between them.
+			return false
+		case other.column == column:
+			// Names of enzymes that cut at the same base are stacked above
+			// each other.
+			continue
+		case other.row < row && column >= other.column && column <= otherEnd:
+			// The line down from the label crosses the other label.
+			return false
+		case other.row > row && other.column >= column && other.column <= end:
+			// The line down from the other label crosses the label.
+			return false
+		}
+	}
+	return true
+}
+
+func markerLine(pad string, start int, end int, markers map[int]bool, marker byte) string {
+	line := []byte(strings.Repeat(" ", end-start))
+	found := false
+	for base := start; base < end; base++ {
+		if markers[base] {
+			line[base-start] = marker
+			found = true
+		}
+	}
+	if !found {
+		return ""
+	}
+	return strings.TrimRight(pad+string(line), " ") + "\n"
+}
+
+// Return a ruler of the bases from start to end with | every ten bases and
+// : every five, counting from the first base of the sequence.
+func ruler(start int, end int) string {
+	marks := make([]byte, end-start)
+	for base := start; base < end; base++ {
+		switch {
+		case (base+1)%10 == 0:
+			marks[base-start] = '|'
+		case (base+1)%5 == 0:
+			marks[base-start] = ':'
+		default:
+			marks[base-start] = '-'
+		}
+	}
+	return string(marks)
+}
+
+// Return the translation of the three forward and three reverse reading
+// frames of the sequence. Each amino acid is written under the middle base
+// of its codon. Codons with bases other than A, C, G and T are written as X.
+func sixFrames(watson string) [6]string {
+	reverse := transform.ReverseComplement(watson)
+	var frames [6]string
+	for frame := 0; frame < 3; frame++ {
+		forward, backward := []byte(strings.Repeat(" ", len(watson))), []byte(strings.Repeat(" ", len(watson)))
+		for i := frame; i+3 <= len(watson); i += 3 {
+			forward[i+1] = translateCodon(watson[i : i+3])
+
+			// The codon starting at i of the reverse complement covers the
+			// watson bases from len-i-3 to len-i.
+			backward[len(watson)-i-2] = translateCodon(reverse[i : i+3])
+		}
+		frames[frame], frames[frame+3] = string(forward), string(backward)
+	}
+	return frames
+}
+
+func translateCodon(bases string) byte {
+	if aminoAcid, ok := codon.GeneticCode[bases]; ok {
+		return aminoAcid
+	}
+	return 'X'
+}
+
+func writeSummary(text *strings.Builder, cutters []enzymeCuts, excluded []enzymeCuts, nonCutters []string) {
+	writeCutters := func(title string, list []enzymeCuts) {
+		fmt.Fprintf(text, "# %s\n\n", title)
+		if len(list) == 0 {
+			text.WriteString("None\n\n")
+			return
+		}
+
+		nameWidth, siteWidth := len("Enzyme"), len("Site")
+		for _, cuts := range list {
+			nameWidth = max(nameWidth, len(cuts.enzyme.Name))
+			siteWidth = max(siteWidth, len(cuts.enzyme.Site))
+		}
+		fmt.Fprintf(text, "%-*s  %-*s  %5s  %s\n", nameWidth, "Enzyme", siteWidth, "Site", "Sites", "Cut positions")
+		for _, cuts := range list {
+			positions := make([]string, len(cuts.watson))
+			for i, position := range cuts.watson {
+				positions[i] = fmt.Sprint(position)
+			}
+			fmt.Fprintf(text, "%-*s  %-*s  %5d  %s\n",
+				nameWidth, cuts.enzyme.Name, siteWidth, cuts.enzyme.Site, cuts.sites, strings.Join(positions, " "))
+		}
+		text.WriteString("\n")
+	}
+
+	writeCutters("Enzymes that cut", cutters)
+	if len(excluded) > 0 {
+		writeCutters("Enzymes excluded by the number of cuts", excluded)
+	}
+
+	text.WriteString("# Enzymes that do not cut\n\n")
+	if len(nonCutters) == 0 {
+		text.WriteString("None\n")
+	}
+	for i := 0; i < len(nonCutters); i += 6 {
+		line := []string{}
+		for _, enzymeName := range nonCutters[i:min(i+6, len(nonCutters))] {
+			line = append(line, fmt.Sprintf("%-12s", enzymeName))
+		}
+		text.WriteString(strings.TrimRight(strings.Join(line, " "), " ") + "\n")
+	}
+}
@@ -0,0 +1,102 @@
+package restrictionmap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/db"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+	"github.com/rmcl/restriction-enzymes/sequence"
+)
+
+func TestWriteText(t *testing.T) {
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["BamHI"], db.Enzymes["HindIII"])
+	dseq := sequence.NewFromWatsonStrand("ATGGATCCAAAG", constants.Linear)
+
+	var text strings.Builder
+	if err := WriteText(&text, "pTest", dseq, &batch, TextOptions{Translate: true}); err != nil {
+		t.Fatalf("Failed to write the map: %s", err)
+	}
+
+	expected := `# pTest, 12 bp, linear
+
+      BamHI
+      \
+  1 ATGGATCCAAAG 12
+    ----:----|--
+    TACCTAGGTTTC
+          /
+ F1  M  D  P  K
+ F2   W  I  Q
+ F3    G  S  K
+ R1  H  I  W  L
+ R2    S  G  F
+ R3   P  D  L
+
+# Enzymes that cut
+
+Enzyme  Site    Sites  Cut positions
+BamHI   GGATCC      1  3
+
+# Enzymes that do not cut
+
+HindIII
+`
+	if text.String() != expected {
+		t.Errorf("Expected the map\n%s\ngot\n%s", expected, text.String())
+	}
+}
+
+func TestWriteTextStaggeredCuts(t *testing.T) {
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["BsaI"], db.Enzymes["EcoRI"])
+	dseq := sequence.NewFromWatsonStrand("AAGGTCTCAAAATTTGAATTCAAAAAAAAAAAAAAAAAAA", constants.Linear)
+
+	var text strings.Builder
+	if err := WriteText(&text, "linear", dseq, &batch, TextOptions{Width: 20}); err != nil {
+		t.Fatalf("Failed to write the map: %s", err)
+	}
+	lines := strings.Split(text.String(), "\n")
+
+	// BsaI cuts the watson strand after base 9 and the crick strand after
+	// base 13. EcoRI cuts after bases 16 and 20.
+	expected := []string{
+		"            BsaI   EcoRI",
+		"            \\      \\",
+		"  1 AAGGTCTCAAAATTTGAATT 20",
+		"    ----:----|----:----|",
+		"    TTCCAGAGTTTTAAACTTAA",
+		"                /      /",
+	}
+	for i, line := range expected {
+		if lines[2+i] != line {
+			t.Errorf("Expected line %d to be %q, got %q", 2+i, line, lines[2+i])
+		}
+	}
+
+	text.Reset()
+	WriteText(&text, "linear", dseq, &batch, TextOptions{MinCuts: 2})
+	if strings.Contains(text.String(), "BsaI\n") || !strings.Contains(text.String(), "# Enzymes excluded by the number of cuts") {
+		t.Errorf("Expected the enzymes that cut once to be excluded, got\n%s", text.String())
+	}
+}
+
+func TestPlaceLabels(t *testing.T) {
+	labels := []textLabel{
+		{column: 0, name: "LongName"},
+		{column: 2, name: "AlwI"},
+		{column: 2, name: "BstYI"},
+		{column: 20, name: "EcoRI"},
+	}
+	rows := placeLabels(labels)
+
+	expectedRows := []int{3, 2, 1, 1}
+	for i, label := range labels {
+		if label.row != expectedRows[i] {
+			t.Errorf("Expected %s on row %d, got %d", label.name, expectedRows[i], label.row)
+		}
+	}
+	if rows != 3 {
+		t.Errorf("Expected 3 rows, got %d", rows)
+	}
+}
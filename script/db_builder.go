@@ -76,6 +76,9 @@ const enzymeRecordTemplate = `"{{.Name}}":{` +
 	`InactivationTemperature:{{.InactivationTemperature}},` +
 	`OptimalTemperature:{{.OptimalTemperature}},` +
 	`Uri:"{{.Uri}}",` +
+	`Prototype:"{{.Prototype}}",` +
+	`Isoschizomers: []string{ {{formatStringList .Isoschizomers}} },` +
+	`Methylation:"{{.Methylation}}",` +
 	`References: []string{ {{formatStringList .References}} },` +
 	`},`
 
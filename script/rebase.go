@@ -29,7 +29,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/bebop/poly/transform"
 	"github.com/rmcl/restriction-enzymes/enzyme"
 	"github.com/secsy/goftp"
 )
@@ -88,7 +87,7 @@ func processEnzymeFile(enzymeFp io.Reader, enzymes *map[string]enzyme.Enzyme) er
 	// Second 5' cut
 	// Second 3' cut
 	enzymePattern := regexp.MustCompile(
-		`^\s*(\S+)\s+(\S+)\s+(\d+)\s+(\d+)\s+(\S+)\s+(\d+)\s+(\d+)\s+(\d+)\s+(\d+)$`)
+		`^\s*(\S+)\s+(\S+)\s+(\d+)\s+(\d+)\s+(\S+)\s+(-?\d+)\s+(-?\d+)\s+(-?\d+)\s+(-?\d+)$`)
 
 	scanner := bufio.NewScanner(enzymeFp)
 	for scanner.Scan() {
@@ -123,6 +122,8 @@ func processEnzymeFile(enzymeFp io.Reader, enzymes *map[string]enzyme.Enzyme) er
 			cuts = enzyme.OneCut
 		case "2":
 			cuts = enzyme.TwoCuts
+		case "4":
+			cuts = enzyme.FourCuts
 		default:
 			return fmt.Errorf("invalid number of cuts: %s", matches[4])
 		}
@@ -138,13 +139,20 @@ func processEnzymeFile(enzymeFp io.Reader, enzymes *map[string]enzyme.Enzyme) er
 			return fmt.Errorf("invalid cut type: %s", matches[5])
 		}
 
+		// Compile the IUPAC recognition site into regular expressions
+		// for the watson and crick strands.
+		regexpFor, regexpRev, err := enzyme.CompileSiteRegexps(matches[2])
+		if err != nil {
+			return err
+		}
+
 		enzymeRecord := enzyme.Enzyme{
 			Name: matches[1],
 			Site: matches[2],
 
 			Length:    intMatches[0],
-			RegexpFor: regexp.MustCompile("(?i)" + matches[2]),
-			RegexpRev: regexp.MustCompile("(?i)" + transform.ReverseComplement(matches[2])),
+			RegexpFor: regexpFor,
+			RegexpRev: regexpRev,
 
 			NumberOfCuts: cuts,
 			CutType:      cutType,
@@ -157,6 +165,15 @@ func processEnzymeFile(enzymeFp io.Reader, enzymes *map[string]enzyme.Enzyme) er
 			ThreePrimeCutSite2: intMatches[4],
 		}
 
+		// Derive the overhang from the first pair of cut sites. The
+		// overhang is unknown if REBASE does not know where the enzyme cuts.
+		if cuts != enzyme.UnknownCuts {
+			enzymeRecord.OverhangLength, enzymeRecord.OverhangSequence = enzyme.CalculateOverhang(
+				enzymeRecord.Site,
+				enzymeRecord.FivePrimeCutSite,
+				enzymeRecord.ThreePrimeCutSite)
+		}
+
 		if _, ok := (*enzymes)[enzymeRecord.Name]; ok {
 			fmt.Printf("duplicate enzyme name: %s", enzymeRecord.Name)
 
@@ -267,6 +284,11 @@ func processBairochFile(bairochFp io.Reader, enzymes *map[string]enzyme.Enzyme)
 		enzyme.Uri = fmt.Sprintf("https://identifiers.org/rebase:%d", enzyme.RebaseId)
 		enzyme.References = record["RA"]
 
+		// The prototype is absent if the enzyme is itself a prototype
+		if prototype, ok := record["PT"]; ok {
+			enzyme.Prototype = prototype[0]
+		}
+
 		// Put the modified enzyme back in map
 		(*enzymes)[enzymeId] = enzyme
 	}
@@ -398,6 +420,18 @@ func getNextReferencesFileRecord(scanner *bufio.Scanner) (*ReferenceRecord, erro
 	return nil, io.EOF
 }
 
+// Split the comma separated isoschizomers line of a references record.
+func splitIsoschizomers(line string) []string {
+	isoschizomers := []string{}
+	for _, name := range strings.Split(line, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			isoschizomers = append(isoschizomers, name)
+		}
+	}
+	return isoschizomers
+}
+
 func processReferencesFile(referencesFp io.Reader) (map[string]ReferenceRecord, error) {
 	scanner := bufio.NewScanner(referencesFp)
 	references := make(map[string]ReferenceRecord)
@@ -470,6 +504,16 @@ func ProcessRebaseFiles(
 		return nil, err
 	}
 
+	// Attach the isoschizomers and methylation line from the references
+	// file to each enzyme
+	for enzymeName, reference := range references {
+		if enzymeRecord, ok := enzymes[enzymeName]; ok {
+			enzymeRecord.Isoschizomers = splitIsoschizomers(reference.Isoschizomers)
+			enzymeRecord.Methylation = reference.Methylation
+			enzymes[enzymeName] = enzymeRecord
+		}
+	}
+
 	data := RebaseData{
 		Enzymes:    enzymes,
 		Suppliers:  suppliers,
@@ -109,7 +109,96 @@ func TestProcessEnzymeFile(t *testing.T) {
 			t.Fatalf("Expected EcoRI enzyme to have sticky end, got %s", EcoRI.CutType)
 		}
 
+		if EcoRI.OverhangLength != -4 || EcoRI.OverhangSequence != "AATT" {
+			t.Fatalf("Expected EcoRI enzyme to have a -4 AATT overhang, got %d %s", EcoRI.OverhangLength, EcoRI.OverhangSequence)
+		}
+
 	} else {
 		t.Fatalf("Expected EcoRI enzyme to be present")
 	}
 }
+
+func TestProcessEnzymeFileDegenerateSite(t *testing.T) {
+	input := `
+	AasI    GACNNNNNNGTC    12      2       0       7       5       0       0
+	MflI    RGATCY  6       2       0       1       5       0       0
+	`
+
+	enzymes := make(map[string]enzyme.Enzyme)
+	err := processEnzymeFile(strings.NewReader(input), &enzymes)
+	if err != nil {
+		t.Fatalf("Error processing enzyme file: %v", err)
+	}
+
+	AasI := enzymes["AasI"]
+	if !AasI.RegexpFor.MatchString("GACACGTACGTC") {
+		t.Fatalf("Expected AasI to match GACACGTACGTC, got pattern %s", AasI.RegexpFor)
+	}
+
+	MflI := enzymes["MflI"]
+	if MflI.RegexpFor.String() != "(?i)[AG]GATC[CT]" {
+		t.Fatalf("Expected MflI pattern (?i)[AG]GATC[CT], got %s", MflI.RegexpFor)
+	}
+	if MflI.RegexpFor.MatchString("RGATCY") {
+		t.Fatalf("Expected MflI not to match the literal site RGATCY")
+	}
+}
+
+func TestProcessEnzymeFileTypeIIB(t *testing.T) {
+	input := `
+	BaeI    ACNNNNGTAYC     11      4       0       -11     -16     23      18
+	`
+
+	enzymes := make(map[string]enzyme.Enzyme)
+	err := processEnzymeFile(strings.NewReader(input), &enzymes)
+	if err != nil {
+		t.Fatalf("Error processing enzyme file: %v", err)
+	}
+
+	BaeI, ok := enzymes["BaeI"]
+	if !ok {
+		t.Fatalf("Expected BaeI enzyme to be present")
+	}
+
+	if BaeI.NumberOfCuts != enzyme.FourCuts {
+		t.Fatalf("Expected BaeI enzyme to have 4 cuts, got %d", BaeI.NumberOfCuts)
+	}
+	if BaeI.FivePrimeCutSite != -11 || BaeI.ThreePrimeCutSite != -16 {
+		t.Fatalf("Expected BaeI first cut sites -11/-16, got %d/%d", BaeI.FivePrimeCutSite, BaeI.ThreePrimeCutSite)
+	}
+	if BaeI.FivePrimeCutSite2 != 23 || BaeI.ThreePrimeCutSite2 != 18 {
+		t.Fatalf("Expected BaeI second cut sites 23/18, got %d/%d", BaeI.FivePrimeCutSite2, BaeI.ThreePrimeCutSite2)
+	}
+}
+
+func TestCreateEnzymeFileTypeIIB(t *testing.T) {
+	input := `
+	BaeI    ACNNNNGTAYC     11      4       0       -11     -16     23      18
+	`
+
+	enzymes := make(map[string]enzyme.Enzyme)
+	if err := processEnzymeFile(strings.NewReader(input), &enzymes); err != nil {
+		t.Fatalf("Error processing enzyme file: %v", err)
+	}
+
+	source, err := createEnzymeFile(enzymes)
+	if err != nil {
+		t.Fatalf("Error creating enzyme file: %v", err)
+	}
+	for _, field := range []string{"FivePrimeCutSite:-11,", "ThreePrimeCutSite:-16,", "FivePrimeCutSite2:23,", "ThreePrimeCutSite2:18,"} {
+		if !strings.Contains(source, field) {
+			t.Errorf("Expected the generated BaeI record to contain %s", field)
+		}
+	}
+}
+
+func TestSplitIsoschizomers(t *testing.T) {
+	isoschizomers := splitIsoschizomers("AccB7I,PflMI, Van91I")
+	if len(isoschizomers) != 3 || isoschizomers[2] != "Van91I" {
+		t.Fatalf("Expected 3 isoschizomers, got %v", isoschizomers)
+	}
+
+	if len(splitIsoschizomers("")) != 0 {
+		t.Fatalf("Expected no isoschizomers for an empty line")
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Package seqio reads sequence files into search inputs and writes the sites
+found by a RestrictionBatch as features of GenBank records, for sequence
+viewers and LIMS that ingest annotated GenBank.
+*/
+package seqio
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bebop/poly/io/genbank"
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+// The GenBank feature key and note of the features added for restriction
+// sites.
+const (
+	RestrictionSiteFeatureType = "misc_feature"
+	RestrictionSiteNote        = "restriction_site"
+)
+
+// Create a GenBank record of a sequence with no features, e.g. to annotate
+// a sequence read from a FASTA file.
+func NewGenbankRecord(name string, sequence string, isCircular bool) genbank.Genbank {
+	return genbank.Genbank{
+		Meta: genbank.Meta{
+			Name: name,
+			Locus: genbank.Locus{
+				Name:            name,
+				SequenceLength:  strconv.Itoa(len(sequence)),
+				MoleculeType:    "DNA",
+				GenbankDivision: "SYN",
+				Circular:        isCircular,
+			},
+		},
+		Features: []genbank.Feature{},
+		Sequence: strings.ToLower(sequence),
+	}
+}
+
+/*
+Add a feature for every site of the batch in the record, after its existing
+features. The topology is taken from the LOCUS line of the record.
+
+Each site becomes a misc_feature spanning its recognition site, with the
+qualifiers:
+
+	/label="EcoRI"
+	/note="restriction_site"
+	/enzyme="EcoRI"
+	/rebase_uri="https://identifiers.org/rebase:993"
+	/cut_site="2/6"
+	/overhang="5' AATT"
+
+The strand of the site is given by the location, which is a complement if
+the site is read from the crick strand.
+The cut site is the position of the base before the cut in the watson and
+crick strands, as in EMBOSS. Type IIB enzymes list both pairs of cuts, e.g.
+"10/5,43/38". The overhang is "blunt" for blunt cuts and left out if the cut
+positions of the enzyme are unknown.
+*/
+func AnnotateGenbank(record *genbank.Genbank, batch *enzyme.RestrictionBatch) {
+	isCircular := record.Meta.Locus.Circular
+	for _, result := range batch.FindAll(record.Sequence, isCircular) {
+		feature := RestrictionSiteFeature(result, len(record.Sequence), isCircular)
+		record.AddFeature(&feature)
+	}
+}
+
+// Return the GenBank feature of a site found in a sequence of the given
+// length. See AnnotateGenbank for its qualifiers.
+func RestrictionSiteFeature(result enzyme.RecognitionSiteResult, length int, isCircular bool) genbank.Feature {
+	start, end := result.RecognitionSiteIndex, result.RecognitionSiteIndex+result.Enzyme.Length
+	location := genbank.Location{Start: start, End: end}
+	if end > length && isCircular {
+		// The site spans the origin.
+		location = genbank.Location{
+			Join: true,
+			SubLocations: []genbank.Location{
+				{Start: start, End: length},
+				{Start: 0, End: end - length},
+			},
+		}
+	}
+	location.Complement = result.Strand == constants.Crick
+
+	cutPosition := func(cut int) int {
+		cut, _ = enzyme.NormalizeCut(cut, length, isCircular)
+		return cut
+	}
+	cutSite := fmt.Sprintf("%d/%d", cutPosition(result.WatsonCutIndex), cutPosition(result.CrickCutIndex))
+	if result.HasSecondCut {
+		cutSite += fmt.Sprintf(",%d/%d", cutPosition(result.WatsonCutIndex2), cutPosition(result.CrickCutIndex2))
+	}
+
+	attributes := map[string]string{
+		"label":    result.Enzyme.Name,
+		"note":     RestrictionSiteNote,
+		"enzyme":   result.Enzyme.Name,
+		"cut_site": cutSite,
+	}
+	if result.Enzyme.Uri != "" {
+		attributes["rebase_uri"] = result.Enzyme.Uri
+	}
+
+	// The overhang bases are unknown if they run past the end of a linear
+	// sequence.
+	switch result.Enzyme.OverhangType() {
+	case enzyme.BluntOverhang:
+		attributes["overhang"] = "blunt"
+	case enzyme.FivePrimeOverhang:
+		attributes["overhang"] = strings.TrimSpace("5' " + result.Overhang)
+	case enzyme.ThreePrimeOverhang:
+		attributes["overhang"] = strings.TrimSpace("3' " + result.Overhang)
+	}
+
+	return genbank.Feature{
+		Type:       RestrictionSiteFeatureType,
+		Attributes: attributes,
+		Location:   location,
+	}
+}
+
+// Write the record, with a feature for every site of the batch added to its
+// existing features, in GenBank format. The record itself is not changed.
+func WriteGenbank(writer io.Writer, record genbank.Genbank, batch *enzyme.RestrictionBatch) error {
+	record.Features = append([]genbank.Feature{}, record.Features...)
+	AnnotateGenbank(&record, batch)
+
+	data, err := genbank.Build(record)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+// Return the first base and the base after the last of a location. A join
+// whose parts run across the origin starts with its first part and ends
+// with its last.
+func LocationSpan(location genbank.Location) (int, int) {
+	if len(location.SubLocations) == 0 {
+		return location.Start, location.End
+	}
+
+	parts := append([]genbank.Location{}, location.SubLocations...)
+	allComplement := true
+	for _, part := range parts {
+		allComplement = allComplement && IsComplement(part)
+	}
+	if allComplement && len(parts) > 1 {
+		// A join of complements lists its parts from the 5' end of the
+		// crick strand, the reverse of their order on the watson strand.
+		for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+			parts[i], parts[j] = parts[j], parts[i]
+		}
+	}
+
+	start, _ := LocationSpan(parts[0])
+	_, end := LocationSpan(parts[len(parts)-1])
+	return start, end
+}
+
+// Return true if the location is on the complement strand, including a join
+// of a single complement location.
+func IsComplement(location genbank.Location) bool {
+	if location.Complement {
+		return true
+	}
+	return len(location.SubLocations) == 1 && IsComplement(location.SubLocations[0])
+}
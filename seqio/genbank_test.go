@@ -0,0 +1,119 @@
+package seqio
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bebop/poly/io/genbank"
+	"github.com/rmcl/restriction-enzymes/db"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+func findFeature(t *testing.T, features []genbank.Feature, enzymeName string) genbank.Feature {
+	t.Helper()
+	for _, feature := range features {
+		if feature.Attributes["enzyme"] == enzymeName {
+			return feature
+		}
+	}
+	t.Fatalf("expected a feature for %s", enzymeName)
+	return genbank.Feature{}
+}
+
+func TestAnnotateGenbank(t *testing.T) {
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["EcoRI"], db.Enzymes["BsaI"], db.Enzymes["DpnI"])
+	record := NewGenbankRecord("test", "AGAATTCAAAAAGAGACCAAAAAAAAAAAA", false)
+	AnnotateGenbank(&record, &batch)
+
+	if len(record.Features) != 2 {
+		t.Fatalf("expected 2 features, got %d", len(record.Features))
+	}
+
+	ecoRI := findFeature(t, record.Features, "EcoRI")
+	if ecoRI.Type != RestrictionSiteFeatureType || ecoRI.Attributes["note"] != RestrictionSiteNote {
+		t.Errorf("expected a %s %s feature, got %s %s",
+			RestrictionSiteFeatureType, RestrictionSiteNote, ecoRI.Type, ecoRI.Attributes["note"])
+	}
+	if ecoRI.Location.Start != 1 || ecoRI.Location.End != 7 || ecoRI.Location.Complement {
+		t.Errorf("expected EcoRI at 1..7 on the watson strand, got %+v", ecoRI.Location)
+	}
+	expected := map[string]string{
+		"label":      "EcoRI",
+		"cut_site":   "2/6",
+		"overhang":   "5' AATT",
+		"rebase_uri": "https://identifiers.org/rebase:993",
+	}
+	for key, value := range expected {
+		if ecoRI.Attributes[key] != value {
+			t.Errorf("expected EcoRI %s %q, got %q", key, value, ecoRI.Attributes[key])
+		}
+	}
+
+	bsaI := findFeature(t, record.Features, "BsaI")
+	if bsaI.Location.Start != 12 || bsaI.Location.End != 18 || !bsaI.Location.Complement {
+		t.Errorf("expected BsaI at complement 12..18, got %+v", bsaI.Location)
+	}
+	if bsaI.Attributes["cut_site"] != "7/11" {
+		t.Errorf("expected BsaI cutting at 7/11, got %s", bsaI.Attributes["cut_site"])
+	}
+	if _, ok := bsaI.Attributes["strand"]; ok {
+		t.Errorf("expected the strand to be given only by the complement location")
+	}
+}
+
+func TestAnnotateGenbankAcrossOrigin(t *testing.T) {
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["EcoRI"])
+	record := NewGenbankRecord("test", "ATTCAAAAAAAAGA", true)
+	AnnotateGenbank(&record, &batch)
+
+	if len(record.Features) != 1 {
+		t.Fatalf("expected 1 feature, got %d", len(record.Features))
+	}
+	location := record.Features[0].Location
+	if !location.Join || len(location.SubLocations) != 2 ||
+		location.SubLocations[0].Start != 12 || location.SubLocations[0].End != 14 ||
+		location.SubLocations[1].Start != 0 || location.SubLocations[1].End != 4 {
+		t.Errorf("expected a join of 12..14 and 0..4, got %+v", location)
+	}
+	if cutSite := record.Features[0].Attributes["cut_site"]; cutSite != "13/3" {
+		t.Errorf("expected cut site 13/3, got %s", cutSite)
+	}
+}
+
+func TestWriteGenbankPreservesFeatures(t *testing.T) {
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["EcoRI"], db.Enzymes["BsaI"])
+	record := NewGenbankRecord("test", "AGAATTCAAAAAGAGACCAAAAAAAAAAAA", true)
+	record.AddFeature(&genbank.Feature{
+		Type:       "CDS",
+		Attributes: map[string]string{"label": "orf"},
+		Location:   genbank.Location{Start: 3, End: 27},
+	})
+
+	var output bytes.Buffer
+	if err := WriteGenbank(&output, record, &batch); err != nil {
+		t.Fatal(err)
+	}
+	if len(record.Features) != 1 {
+		t.Errorf("expected the record to keep 1 feature, got %d", len(record.Features))
+	}
+
+	parsed, err := genbank.Parse(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	features := parsed.Features
+	if len(features) != 3 {
+		t.Fatalf("expected 3 features, got %d", len(features))
+	}
+	if features[0].Type != "CDS" || features[0].Attributes["label"] != "orf" {
+		t.Errorf("expected the CDS first, got %s %v", features[0].Type, features[0].Attributes)
+	}
+
+	bsaI := findFeature(t, features, "BsaI")
+	if !bsaI.Location.Complement || bsaI.Attributes["overhang"] == "" {
+		t.Errorf("expected a complement BsaI feature with an overhang, got %+v %v", bsaI.Location, bsaI.Attributes)
+	}
+	if parsed.Sequence != record.Sequence {
+		t.Errorf("expected sequence %s, got %s", record.Sequence, parsed.Sequence)
+	}
+}
@@ -0,0 +1,90 @@
+package seqio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bebop/poly/io/fasta"
+	"github.com/bebop/poly/io/genbank"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+// Read every record of a FASTA file as a search input. FASTA does not record
+// the topology so every record is given the same one.
+func ReadFastaInputs(path string, isCircular bool) ([]enzyme.SequenceInput, error) {
+	records, err := fasta.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]enzyme.SequenceInput, 0, len(records))
+	for _, record := range records {
+		inputs = append(inputs, enzyme.SequenceInput{
+			Name:       record.Name,
+			Sequence:   record.Sequence,
+			IsCircular: isCircular,
+		})
+	}
+	return inputs, nil
+}
+
+// Read every record of a GenBank file as a search input. The topology is
+// taken from the LOCUS line of each record.
+func ReadGenbankInputs(path string) ([]enzyme.SequenceInput, error) {
+	records, err := genbank.ReadMulti(path)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]enzyme.SequenceInput, 0, len(records))
+	for _, record := range records {
+		inputs = append(inputs, enzyme.SequenceInput{
+			Name:       record.Meta.Locus.Name,
+			Sequence:   record.Sequence,
+			IsCircular: record.Meta.Locus.Circular,
+		})
+	}
+	return inputs, nil
+}
+
+// Read the search inputs from every FASTA (.fa, .fasta, .fna) and GenBank
+// (.gb, .gbk, .genbank) file in a directory, e.g. a directory of plasmids.
+// Files are read in name order and other files are ignored. FASTA does not
+// record the topology so every FASTA record is given the same one; GenBank
+// records take theirs from the LOCUS line.
+func ReadInputDir(dir string, isCircular bool) ([]enzyme.SequenceInput, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	inputs := []enzyme.SequenceInput{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		var fileInputs []enzyme.SequenceInput
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".fa", ".fasta", ".fna":
+			fileInputs, err = ReadFastaInputs(path, isCircular)
+		case ".gb", ".gbk", ".genbank":
+			fileInputs, err = ReadGenbankInputs(path)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", path, err)
+		}
+		inputs = append(inputs, fileInputs...)
+	}
+	return inputs, nil
+}
@@ -0,0 +1,68 @@
+package seqio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rmcl/restriction-enzymes/db"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+func TestReadInputDir(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"b.fasta":   ">second\nGAATTC\n>third\nGGTCTC\n",
+		"a.fa":      ">first\nAAAAAA\n",
+		"notes.txt": "not a sequence",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, isCircular := range []bool{false, true} {
+		inputs, err := ReadInputDir(dir, isCircular)
+		if err != nil {
+			t.Fatalf("Error reading inputs: %v", err)
+		}
+
+		names := []string{}
+		for _, input := range inputs {
+			names = append(names, input.Name)
+			if input.IsCircular != isCircular {
+				t.Errorf("Expected %s to be circular %t", input.Name, isCircular)
+			}
+		}
+		if fmt.Sprint(names) != "[first second third]" {
+			t.Errorf("Expected [first second third], got %v", names)
+		}
+	}
+}
+
+func TestReadGenbankInputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plasmid.gb")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := enzyme.NewRestrictionBatch(db.Enzymes["EcoRI"])
+	if err := WriteGenbank(file, NewGenbankRecord("plasmid", "AAGAATTCAA", true), &batch); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The topology is read from the LOCUS line, not the argument.
+	inputs, err := ReadInputDir(filepath.Dir(path), false)
+	if err != nil {
+		t.Fatalf("Error reading inputs: %v", err)
+	}
+	if len(inputs) != 1 || inputs[0].Name != "plasmid" || !inputs[0].IsCircular {
+		t.Errorf("Expected the circular plasmid, got %+v", inputs)
+	}
+}
@@ -5,6 +5,7 @@ package sequence
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/bebop/poly/transform"
 	"github.com/rmcl/restriction-enzymes/constants"
@@ -70,75 +71,44 @@ Takes either a single enzyme or a batch of enzymes (RestrictionBatch).
 
 Returns a slice of Dseqs that represent the fragments of the Dseq after cutting with the enzyme.
 
-If the enzyme is circular, the sequence is treated as circular and the
-function will return the next recognition site, even if it spans the
-beginning and end of the sequence.
+If the Dseq is circular, sites spanning the origin are found as well and
+the circle is opened at the cuts, so n cuts give n linear fragments. The
+fragment spanning the origin is returned first. A circular Dseq without a
+cut is returned unchanged.
+
+Type IIB enzymes cut on both sides of their recognition site. The small
+fragment excised between the two cuts, which contains the recognition site,
+is not included in the returned fragments.
 */
 func (dSeq *Dseq) Cut(enzyme Cutter) []Dseq {
-	fragments := make([]Dseq, 0)
+	cuts, excised := dSeq.findCuts(enzyme)
+	if dSeq.Geometry == constants.Circular {
+		return dSeq.cutCircular(cuts, excised)
+	}
 
-	nextSearchStart := 0
-	lastOverhang := 0
+	fragments := make([]Dseq, 0)
 
 	lastCrickCutIndex := 0
 	lastWatsonCutIndex := 0
 
-	for {
-		results := enzyme.GetNextRecognitionSite(
-			dSeq.Watson,
-			nextSearchStart,
-			dSeq.Geometry == constants.Circular,
-		)
-		if results == nil {
-			break
-		}
-
-		var watsonCutIndex, crickCutIndex int
+	for _, cut := range cuts {
+		watsonCutIndex := cut.watson
+		crickCutIndex := cut.crick
 
-		if len(results) > 1 {
-			fmt.Println("Warning: multiple recognition sites found. Only the first site will be used.")
+		// Staggered cuts from neighbouring sites may cross on the crick
+		// strand. Never slice the crick strand backwards.
+		if crickCutIndex < lastCrickCutIndex {
+			crickCutIndex = lastCrickCutIndex
 		}
-		result := results[0]
-		enzyme := result.Enzyme
-
-		if result.Strand == constants.Watson {
-			watsonCutIndex = result.RecognitionSiteIndex + enzyme.FivePrimeCutSite
-			crickCutIndex = result.RecognitionSiteIndex + enzyme.ThreePrimeCutSite
-
-			if watsonCutIndex >= len(dSeq.Watson) {
-				watsonCutIndex = len(dSeq.Watson)
-			}
-
-			if crickCutIndex >= len(dSeq.Crick) {
-				crickCutIndex = len(dSeq.Crick)
-			}
-
-			fragment := Dseq{
-				Watson:   dSeq.Watson[lastWatsonCutIndex:watsonCutIndex],
-				Crick:    dSeq.Crick[lastCrickCutIndex:crickCutIndex],
-				Overhang: lastOverhang,
-				Geometry: constants.Linear,
-			}
-			fragments = append(fragments, fragment)
-
-			lastOverhang = (crickCutIndex - watsonCutIndex) * -1
-			nextSearchStart = watsonCutIndex
-
-		} else {
-			watsonCutIndex = result.RecognitionSiteIndex + enzyme.Length - enzyme.ThreePrimeCutSite
-			crickCutIndex = result.RecognitionSiteIndex + enzyme.Length - enzyme.FivePrimeCutSite
-			overhang := watsonCutIndex - crickCutIndex
 
+		if !isExcised(excised, lastWatsonCutIndex, watsonCutIndex) {
 			fragment := Dseq{
 				Watson:   dSeq.Watson[lastWatsonCutIndex:watsonCutIndex],
 				Crick:    dSeq.Crick[lastCrickCutIndex:crickCutIndex],
-				Overhang: overhang,
+				Overhang: lastWatsonCutIndex - lastCrickCutIndex,
 				Geometry: constants.Linear,
 			}
 			fragments = append(fragments, fragment)
-
-			nextSearchStart = result.RecognitionSiteIndex + 1
-
 		}
 
 		lastCrickCutIndex = crickCutIndex
@@ -149,10 +119,155 @@ func (dSeq *Dseq) Cut(enzyme Cutter) []Dseq {
 	fragment := Dseq{
 		Watson:   dSeq.Watson[lastWatsonCutIndex:],
 		Crick:    dSeq.Crick[lastCrickCutIndex:],
-		Overhang: lastOverhang,
+		Overhang: lastWatsonCutIndex - lastCrickCutIndex,
 		Geometry: dSeq.Geometry,
 	}
 	fragments = append(fragments, fragment)
 
 	return fragments
 }
+
+// Open a circular Dseq at the cuts. Cuts of a circular sequence have a
+// watson index within the sequence but the crick index may lie past either
+// end of it. The fragment from the last cut around the origin to the first
+// cut is returned first.
+func (dSeq *Dseq) cutCircular(cuts []cutPosition, excised [][2]int) []Dseq {
+	if len(cuts) == 0 {
+		return []Dseq{*dSeq}
+	}
+
+	length := len(dSeq.Watson)
+	fragments := make([]Dseq, 0, len(cuts))
+	for i := range cuts {
+		start := cuts[(i+len(cuts)-1)%len(cuts)]
+		end := cuts[i]
+		if i == 0 {
+			start.watson -= length
+			start.crick -= length
+		}
+
+		// Staggered cuts from neighbouring sites may cross on the crick
+		// strand. Never slice the crick strand backwards.
+		crickEnd := max(end.crick, start.crick)
+
+		if isExcised(excised, start.watson, end.watson) || isExcised(excised, start.watson+length, end.watson+length) {
+			continue
+		}
+		fragments = append(fragments, Dseq{
+			Watson:   circularSlice(dSeq.Watson, start.watson, end.watson),
+			Crick:    circularSlice(dSeq.Crick, start.crick, crickEnd),
+			Overhang: start.watson - start.crick,
+			Geometry: constants.Linear,
+		})
+	}
+	return fragments
+}
+
+// Return the bases of a circular sequence from start up to end. Either may
+// lie outside the sequence, and the slice continues around the origin.
+func circularSlice(sequence string, start, end int) string {
+	length := len(sequence)
+	slice := make([]byte, 0, max(end-start, 0))
+	for i := start; i < end; i++ {
+		slice = append(slice, sequence[((i%length)+length)%length])
+	}
+	return string(slice)
+}
+
+// A single double stranded cut made by an enzyme.
+type cutPosition struct {
+	watson int
+	crick  int
+}
+
+// Find every cut the enzyme makes in the Dseq, sorted by their watson strand
+// index. Cut positions of a linear sequence are clamped to its bounds. Cuts
+// of a circular sequence are wrapped around the origin so the watson index
+// is within the sequence, keeping the distance to the crick index. The
+// second return value holds the watson strand intervals excised by Type IIB
+// enzymes.
+func (dSeq *Dseq) findCuts(enzyme Cutter) ([]cutPosition, [][2]int) {
+	cuts := []cutPosition{}
+	excised := [][2]int{}
+	seen := map[cutPosition]bool{}
+	isCircular := dSeq.Geometry == constants.Circular
+
+	addCut := func(watsonCutIndex, crickCutIndex int) cutPosition {
+		cut := cutPosition{
+			watson: clampIndex(watsonCutIndex, len(dSeq.Watson)),
+			crick:  clampIndex(crickCutIndex, len(dSeq.Crick)),
+		}
+		if isCircular {
+			length := len(dSeq.Watson)
+			wrapped := ((watsonCutIndex % length) + length) % length
+			cut = cutPosition{watson: wrapped, crick: crickCutIndex + wrapped - watsonCutIndex}
+		}
+		if !seen[cut] {
+			seen[cut] = true
+			cuts = append(cuts, cut)
+		}
+		return cut
+	}
+
+	nextSearchStart := 0
+	for {
+		results := enzyme.GetNextRecognitionSite(
+			dSeq.Watson,
+			nextSearchStart,
+			isCircular,
+		)
+		if results == nil {
+			break
+		}
+
+		for _, result := range results {
+			firstCut := addCut(result.WatsonCutIndex, result.CrickCutIndex)
+
+			if result.HasSecondCut {
+				secondCut := addCut(result.WatsonCutIndex2, result.CrickCutIndex2)
+
+				// The cuts are on either side of the site, so the
+				// excised region runs from the first cut to the second
+				// even if it spans the origin of a circular sequence.
+				span := result.WatsonCutIndex2 - result.WatsonCutIndex
+				start := firstCut.watson
+				if span < 0 {
+					span, start = -span, secondCut.watson
+				}
+				excised = append(excised, [2]int{start, start + span})
+			}
+		}
+
+		nextSearchStart = results[0].RecognitionSiteIndex + 1
+	}
+
+	sort.Slice(cuts, func(i, j int) bool {
+		if cuts[i].watson == cuts[j].watson {
+			return cuts[i].crick < cuts[j].crick
+		}
+		return cuts[i].watson < cuts[j].watson
+	})
+
+	return cuts, excised
+}
+
+// Return true if the fragment between start and end lies within a region
+// excised by a Type IIB enzyme.
+func isExcised(excised [][2]int, start, end int) bool {
+	for _, region := range excised {
+		if start >= region[0] && end <= region[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func clampIndex(index, length int) int {
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
@@ -94,11 +94,19 @@ func TestCutWithRestrictionBatchCircularCutLoop(t *testing.T) {
 	// GGTCTC cut site should wrap around
 	dSeq1 := NewFromWatsonStrand("AAAGGTCTCNCACANNNNCCAA", constants.Circular)
 
+	// Cut used to also open the circle at the origin and return the two
+	// pieces on either side of it:
+	//
 	// AAAGGTCTCN
 	// TTTCCAGAGNGTGT
-
+	//
 	// CACANNNNCCAA
 	//     NNNNGGTT
+	//
+	// The single BsaI cut opens the circle into one linear fragment, the
+	// second piece joined to the first across the origin.
+	expectedWatson := "CACANNNNCCAA" + "AAAGGTCTCN"
+	expectedCrick := "NNNNGGTT" + "TTTCCAGAGNGTGT"
 
 	batch := enzyme.NewRestrictionBatch(
 		db.Enzymes["BsaI"],
@@ -106,30 +114,55 @@ func TestCutWithRestrictionBatchCircularCutLoop(t *testing.T) {
 
 	results := dSeq1.Cut(&batch)
 
-	if len(results) != 2 {
-		t.Errorf("Expected 2 fragment, got %d", len(results))
-		t.Fail()
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 fragment, got %d", len(results))
 	}
 
-	if results[0].Watson != "AAAGGTCTCN" {
-		t.Errorf("Expected AAAGGTCTCN, got %s", results[0].Watson)
-		t.Fail()
+	if results[0].Watson != expectedWatson {
+		t.Errorf("Expected %s, got %s", expectedWatson, results[0].Watson)
 	}
 
-	if results[0].Crick != "TTTCCAGAGNGTGT" {
-		t.Errorf("Expected TTTCCAGAGNGTGT, got %s", results[0].Crick)
-		t.Fail()
+	if results[0].Crick != expectedCrick {
+		t.Errorf("Expected %s, got %s", expectedCrick, results[0].Crick)
 	}
 
-	if results[1].Watson != "CACANNNNCCAA" {
-		t.Errorf("Expected CACANNNNCCAA, got %s", results[1].Watson)
-		t.Fail()
+	if results[0].Overhang != -4 || results[0].Geometry != constants.Linear {
+		t.Errorf("Expected a linear fragment with an overhang of -4, got %d %s", results[0].Overhang, results[0].Geometry)
 	}
-	if results[1].Crick != "NNNNGGTT" {
-		t.Errorf("Expected NNNNGGTT, got %s", results[1].Crick)
-		t.Fail()
+}
+
+func TestCutCircularJoinsFragmentsAcrossOrigin(t *testing.T) {
+	// Two EcoRI sites in a circle give two fragments. The one spanning the
+	// origin is returned first.
+	watson := "CCCGAATTCAAAAAAAAAAGAATTCGGG"
+	dSeq := NewFromWatsonStrand(watson, constants.Circular)
+	EcoRI := db.Enzymes["EcoRI"]
+
+	fragments := dSeq.Cut(&EcoRI)
+	if len(fragments) != 2 {
+		t.Fatalf("Expected 2 fragments, got %d", len(fragments))
 	}
 
+	expected := []string{
+		"AATTCGGGCCCG", "GCCCGGGCTTAA",
+		"AATTCAAAAAAAAAAG", "GTTTTTTTTTTCTTAA",
+	}
+	actual := []string{
+		fragments[0].Watson, fragments[0].Crick,
+		fragments[1].Watson, fragments[1].Crick,
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected fragments %v, got %v", expected, actual)
+	}
+	if len(fragments[0].Watson)+len(fragments[1].Watson) != len(watson) {
+		t.Errorf("Expected the fragments to cover the circle once")
+	}
+
+	// A circle without a site is returned unchanged.
+	fragments = NewFromWatsonStrand("AAAAAAAA", constants.Circular).Cut(&EcoRI)
+	if len(fragments) != 1 || fragments[0].Geometry != constants.Circular {
+		t.Errorf("Expected the uncut circle, got %v", fragments)
+	}
 }
 
 func TestRandomSequenceNotWorking(t *testing.T) {
@@ -149,3 +182,69 @@ func TestRandomSequenceNotWorking(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestCutTypeIIBRemovesExcisedFragment(t *testing.T) {
+	regexpFor, regexpRev, err := enzyme.CompileSiteRegexps("ACNNNNGTAYC")
+	if err != nil {
+		t.Fatalf("Error compiling BaeI site: %v", err)
+	}
+
+	// BaeI (10/15)ACNNNNGTAYC(12/7)
+	BaeI := enzyme.Enzyme{
+		Name:               "BaeI",
+		Site:               "ACNNNNGTAYC",
+		Length:             11,
+		RegexpFor:          regexpFor,
+		RegexpRev:          regexpRev,
+		NumberOfCuts:       enzyme.FourCuts,
+		CutType:            enzyme.StickyEnd,
+		FivePrimeCutSite:   -11,
+		ThreePrimeCutSite:  -16,
+		FivePrimeCutSite2:  23,
+		ThreePrimeCutSite2: 18,
+	}
+
+	dSeq := NewFromWatsonStrand("AAAAAAAAAACCCCCCCCCCACAAAAGTACCGGGGGGGGGGTTTTTTTTTT", constants.Linear)
+	fragments := dSeq.Cut(&BaeI)
+
+	if len(fragments) != 2 {
+		t.Fatalf("Expected 2 fragments, got %d, Fragments: %v", len(fragments), fragments)
+	}
+
+	expected := []string{
+		"AAAAAAAAAA", "TTTTT",
+		"TTTTTTTT", "CCCAAAAAAAAAA",
+	}
+
+	actual := []string{
+		fragments[0].Watson, fragments[0].Crick,
+		fragments[1].Watson, fragments[1].Crick,
+	}
+
+	if reflect.DeepEqual(expected, actual) == false {
+		t.Errorf("Expected fragments %v, got %v", expected, actual)
+	}
+
+	if fragments[1].Overhang != 5 {
+		t.Errorf("Expected overhang of 5, got %d", fragments[1].Overhang)
+	}
+}
+
+func TestCutWithMethylationFilter(t *testing.T) {
+	// The first AccIII site overlaps a GATC Dam site
+	dSeq := NewFromWatsonStrand("AAAATCCGGATCAAAAAATCCGGAAAAAA", constants.Linear)
+
+	AccIII := db.Enzymes["AccIII"]
+	// The methylation line of AccIII in REBASE.
+	AccIII.Methylation = "6(6)"
+
+	fragments := dSeq.Cut(enzyme.NewMethylationFilter(&AccIII, enzyme.DH5Alpha))
+	if len(fragments) != 2 {
+		t.Errorf("Expected 2 fragments from dam+ DNA, got %d", len(fragments))
+	}
+
+	fragments = dSeq.Cut(enzyme.NewMethylationFilter(&AccIII, enzyme.DamDcmNegative))
+	if len(fragments) != 3 {
+		t.Errorf("Expected 3 fragments from dam- DNA, got %d", len(fragments))
+	}
+}
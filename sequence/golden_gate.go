@@ -0,0 +1,352 @@
+package sequence
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bebop/poly/transform"
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/enzyme"
+)
+
+// A named part of a Golden Gate assembly, e.g. a promoter on a linear PCR
+// product or a coding sequence in a circular entry vector.
+type Part struct {
+	Name     string
+	Sequence Dseq
+}
+
+// A part used in a product of an assembly. Reversed is true if the part is
+// inserted as its reverse complement.
+type ProductPart struct {
+	Name     string
+	Reversed bool
+}
+
+// A circular product of a Golden Gate assembly.
+type Product struct {
+	// The parts in the order they are joined, starting with the first part
+	// passed to the assembly that is used in the product.
+	Parts []ProductPart
+
+	// The overhangs, read from the watson strand of the product, joining
+	// each part to the next. The last fusion site closes the circle.
+	FusionSites []string
+
+	Sequence Dseq
+}
+
+// A recognition site of an assembly enzyme found in a part.
+type PartSite struct {
+	Part string
+	Site enzyme.RecognitionSiteResult
+}
+
+// The result of simulating a one-pot Golden Gate assembly.
+type GoldenGateResult struct {
+	// Every circular product the reaction can form. The same parts joined
+	// in the reverse direction are only listed once.
+	Products []Product
+
+	// The parts that are not used by any product.
+	UnusedParts []string
+
+	// Fusion sites shared by more than two fragment ends. These ends compete
+	// for the same partner and can form unintended products. Fusion sites
+	// are written as the lesser of the overhang and its reverse complement.
+	DuplicateFusionSites []string
+
+	// Fusion sites that are their own reverse complement. A part with a
+	// palindromic fusion site can be ligated in either orientation.
+	PalindromicFusionSites []string
+
+	// The sites of parts with more than the two sites expected to flank a
+	// part. The sites of a linear part between its first and last sites are
+	// reported. Every site of a circular part is reported.
+	InternalSites []PartSite
+}
+
+// One end of a digested fragment. The overhang is read from the watson
+// strand. Blunt ends have no overhang.
+type fragmentEnd struct {
+	overhangType enzyme.EnzymeOverhangType
+	overhang     string
+}
+
+func (end fragmentEnd) reverseComplement() fragmentEnd {
+	return fragmentEnd{end.overhangType, transform.ReverseComplement(end.overhang)}
+}
+
+// A digested fragment of a part that can be ligated on both ends and does
+// not contain a site of the assembly enzymes.
+type assemblyFragment struct {
+	part     int
+	fragment Dseq
+	left     fragmentEnd
+	right    fragmentEnd
+}
+
+// Return the fragment's ends, and its watson strand, when it is inserted in
+// the given orientation.
+func (fragment *assemblyFragment) oriented(reversed bool) (fragmentEnd, fragmentEnd, string) {
+	if !reversed {
+		return fragment.left, fragment.right, fragment.fragment.Watson
+	}
+	return fragment.right.reverseComplement(),
+		fragment.left.reverseComplement(),
+		reverseString(fragment.fragment.Crick)
+}
+
+/*
+Simulate a one-pot Golden Gate assembly of the parts with one or more Type
+IIS enzymes, e.g. BsaI, BsmBI or BbsI from db.Enzymes.
+
+Each part is digested with Dseq.Cut. A fragment takes part in the assembly if
+both of its ends have overhangs and it does not contain a site of the
+enzymes, since such fragments are cut again in the reaction. Fragments are
+joined wherever an end's overhang pairs with the next fragment's end, in
+either orientation, and every circle that can be closed is returned as a
+product. Each fragment is used at most once per product.
+
+An error is returned if an enzyme's cut positions are unknown or it cuts
+within its recognition site. The number of ways to order the fragments grows
+exponentially with the number of fragments sharing an overhang, so an error
+is also returned if more than maxAssemblySteps orderings would be tried.
+*/
+func GoldenGate(parts []Part, enzymes ...enzyme.Enzyme) (GoldenGateResult, error) {
+	if len(enzymes) == 0 {
+		return GoldenGateResult{}, fmt.Errorf("golden gate assembly needs at least one enzyme")
+	}
+	for i := range enzymes {
+		if !isTypeIIS(&enzymes[i]) {
+			return GoldenGateResult{}, fmt.Errorf("%s is not a Type IIS enzyme", enzymes[i].Name)
+		}
+	}
+
+	batch := enzyme.NewRestrictionBatch(enzymes...)
+	result := GoldenGateResult{
+		Products:               []Product{},
+		UnusedParts:            []string{},
+		DuplicateFusionSites:   []string{},
+		PalindromicFusionSites: []string{},
+		InternalSites:          []PartSite{},
+	}
+
+	fragments := []assemblyFragment{}
+	for i, part := range parts {
+		isCircular := part.Sequence.Geometry == constants.Circular
+		sites := batch.FindAll(part.Sequence.Watson, isCircular)
+		result.InternalSites = append(result.InternalSites, internalSites(part.Name, sites, isCircular)...)
+
+		for _, fragment := range part.Sequence.Cut(&batch) {
+			left, right := fragmentEnds(&fragment)
+			if left.overhangType == enzyme.BluntOverhang || right.overhangType == enzyme.BluntOverhang {
+				continue
+			}
+			if len(batch.FindAll(fragment.Watson, false)) > 0 {
+				continue
+			}
+			fragments = append(fragments, assemblyFragment{i, fragment, left, right})
+		}
+	}
+
+	products, err := assembleProducts(parts, fragments)
+	if err != nil {
+		return GoldenGateResult{}, err
+	}
+	result.Products = products
+
+	used := map[string]bool{}
+	for _, product := range result.Products {
+		for _, part := range product.Parts {
+			used[part.Name] = true
+		}
+	}
+	for _, part := range parts {
+		if !used[part.Name] {
+			result.UnusedParts = append(result.UnusedParts, part.Name)
+		}
+	}
+
+	result.DuplicateFusionSites, result.PalindromicFusionSites = checkFusionSites(fragments)
+
+	return result, nil
+}
+
+// Return true if the enzyme has known cuts that lie outside of its
+// recognition site.
+func isTypeIIS(ez *enzyme.Enzyme) bool {
+	if ez.OverhangType() == enzyme.UnknownOverhang || ez.HasSecondCut() {
+		return false
+	}
+	watsonCut, crickCut := ez.GetCutSitePositions(0, constants.Watson)
+	lowerCut, higherCut := min(watsonCut, crickCut), max(watsonCut, crickCut)
+	return higherCut <= 0 || lowerCut >= ez.Length
+}
+
+// Return the sites of a part that are not expected to flank it.
+func internalSites(name string, sites []enzyme.RecognitionSiteResult, isCircular bool) []PartSite {
+	if len(sites) <= 2 {
+		return nil
+	}
+	if !isCircular {
+		sites = sites[1 : len(sites)-1]
+	}
+
+	partSites := make([]PartSite, len(sites))
+	for i, site := range sites {
+		partSites[i] = PartSite{name, site}
+	}
+	return partSites
+}
+
+// Return the left and right ends of a fragment.
+func fragmentEnds(fragment *Dseq) (fragmentEnd, fragmentEnd) {
+	left := fragmentEnd{overhangType: enzyme.BluntOverhang}
+	switch {
+	case fragment.Overhang < 0:
+		left = fragmentEnd{enzyme.FivePrimeOverhang, fragment.Watson[:-fragment.Overhang]}
+	case fragment.Overhang > 0:
+		left = fragmentEnd{enzyme.ThreePrimeOverhang, transform.Complement(fragment.Crick[:fragment.Overhang])}
+	}
+
+	// The position of the end of the crick strand in watson coordinates.
+	crickEnd := len(fragment.Crick) - fragment.Overhang
+	right := fragmentEnd{overhangType: enzyme.BluntOverhang}
+	switch {
+	case crickEnd > len(fragment.Watson):
+		right = fragmentEnd{enzyme.FivePrimeOverhang, transform.Complement(fragment.Crick[len(fragment.Watson)+fragment.Overhang:])}
+	case crickEnd < len(fragment.Watson):
+		right = fragmentEnd{enzyme.ThreePrimeOverhang, fragment.Watson[crickEnd:]}
+	}
+	return left, right
+}
+
+// A fragment in a product under construction.
+type placedFragment struct {
+	fragment int
+	reversed bool
+}
+
+// The most fragment orderings tried by an assembly before giving up.
+const maxAssemblySteps = 100_000
+
+// Return every circle that can be formed from the fragments. Circles start
+// with their lowest numbered fragment in its forward orientation, so a
+// circle and the same circle read in the reverse direction are found once.
+// An error is returned if more than maxAssemblySteps orderings are tried.
+func assembleProducts(parts []Part, fragments []assemblyFragment) ([]Product, error) {
+	products := []Product{}
+	steps := 0
+
+	var extend func(path []placedFragment, used []bool) bool
+	extend = func(path []placedFragment, used []bool) bool {
+		steps++
+		if steps > maxAssemblySteps {
+			return false
+		}
+
+		first := &fragments[path[0].fragment]
+		last := path[len(path)-1]
+		_, lastRight, _ := fragments[last.fragment].oriented(last.reversed)
+
+		if lastRight == first.left {
+			products = append(products, buildProduct(parts, fragments, path))
+		}
+
+		for next := path[0].fragment + 1; next < len(fragments); next++ {
+			if used[next] {
+				continue
+			}
+			for _, reversed := range []bool{false, true} {
+				nextLeft, _, _ := fragments[next].oriented(reversed)
+				if nextLeft != lastRight {
+					continue
+				}
+				used[next] = true
+				ok := extend(append(path, placedFragment{next, reversed}), used)
+				used[next] = false
+				if !ok {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	for start := range fragments {
+		used := make([]bool, len(fragments))
+		used[start] = true
+		if !extend([]placedFragment{{start, false}}, used) {
+			return nil, fmt.Errorf(
+				"golden gate assembly of %d fragments has more than %d possible orderings",
+				len(fragments), maxAssemblySteps)
+		}
+	}
+
+	return products, nil
+}
+
+func buildProduct(parts []Part, fragments []assemblyFragment, path []placedFragment) Product {
+	product := Product{
+		Parts:       make([]ProductPart, len(path)),
+		FusionSites: make([]string, len(path)),
+	}
+
+	var watson strings.Builder
+	for i, placed := range path {
+		fragment := &fragments[placed.fragment]
+		_, right, fragmentWatson := fragment.oriented(placed.reversed)
+
+		product.Parts[i] = ProductPart{parts[fragment.part].Name, placed.reversed}
+		product.FusionSites[i] = right.overhang
+		watson.WriteString(fragmentWatson)
+	}
+
+	product.Sequence = *NewFromWatsonStrand(watson.String(), constants.Circular)
+	return product
+}
+
+// Return the duplicate and palindromic fusion sites of the fragments,
+// sorted.
+func checkFusionSites(fragments []assemblyFragment) ([]string, []string) {
+	counts := map[fragmentEnd]int{}
+	for _, fragment := range fragments {
+		for _, end := range []fragmentEnd{fragment.left, fragment.right} {
+			counts[canonicalEnd(end)]++
+		}
+	}
+
+	duplicates, palindromes := []string{}, []string{}
+	for end, count := range counts {
+		if count > 2 {
+			duplicates = append(duplicates, end.overhang)
+		}
+		if end == end.reverseComplement() {
+			palindromes = append(palindromes, end.overhang)
+		}
+	}
+
+	sort.Strings(duplicates)
+	sort.Strings(palindromes)
+	return duplicates, palindromes
+}
+
+// Return the end or its reverse complement, whichever has the lesser
+// overhang.
+func canonicalEnd(end fragmentEnd) fragmentEnd {
+	reverse := end.reverseComplement()
+	if reverse.overhang < end.overhang {
+		return reverse
+	}
+	return end
+}
+
+func reverseString(value string) string {
+	reversed := []byte(value)
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	return string(reversed)
+}
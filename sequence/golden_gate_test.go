@@ -0,0 +1,159 @@
+package sequence
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bebop/poly/transform"
+	"github.com/rmcl/restriction-enzymes/constants"
+	"github.com/rmcl/restriction-enzymes/db"
+)
+
+// Return a linear part flanked by inward facing BsaI sites that leave the
+// left and right overhangs.
+func bsaIPart(name string, left string, body string, right string) Part {
+	watson := "GGTCTCA" + left + body + right + "AGAGACC"
+	return Part{name, *NewFromWatsonStrand(watson, constants.Linear)}
+}
+
+func TestGoldenGate(t *testing.T) {
+	promoter := bsaIPart("promoter", "GGAG", "TTGACAATTAATCAT", "TACT")
+	cds := bsaIPart("cds", "TACT", "ATGAAACATCACCAT", "GCTT")
+	backbone := Part{"backbone", *NewFromWatsonStrand(
+		"GCTT"+"CCAAGTTTACGCGTA"+"GGAG"+"AGAGACC"+"AAATTTAAA"+"GGTCTCA",
+		constants.Circular)}
+	unused := bsaIPart("unused", "CGCG", "AAACCCAAA", "TTTT")
+
+	result, err := GoldenGate([]Part{promoter, cds, backbone, unused}, db.Enzymes["BsaI"])
+	if err != nil {
+		t.Fatalf("Failed to simulate the assembly: %s", err)
+	}
+
+	if len(result.Products) != 1 {
+		t.Fatalf("Expected 1 product, got %d: %+v", len(result.Products), result.Products)
+	}
+	product := result.Products[0]
+
+	expectedParts := []ProductPart{{"promoter", false}, {"cds", false}, {"backbone", false}}
+	if !reflect.DeepEqual(product.Parts, expectedParts) {
+		t.Errorf("Expected parts %v, got %v", expectedParts, product.Parts)
+	}
+	if !reflect.DeepEqual(product.FusionSites, []string{"TACT", "GCTT", "GGAG"}) {
+		t.Errorf("Unexpected fusion sites %v", product.FusionSites)
+	}
+
+	expectedSequence := "GGAGTTGACAATTAATCAT" + "TACTATGAAACATCACCAT" + "GCTTCCAAGTTTACGCGTA"
+	if product.Sequence.Watson != expectedSequence || product.Sequence.Geometry != constants.Circular {
+		t.Errorf("Expected product %s, got %s", expectedSequence, product.Sequence.Watson)
+	}
+
+	if !reflect.DeepEqual(result.UnusedParts, []string{"unused"}) {
+		t.Errorf("Expected unused parts [unused], got %v", result.UnusedParts)
+	}
+	if !reflect.DeepEqual(result.PalindromicFusionSites, []string{"CGCG"}) {
+		t.Errorf("Expected palindromic fusion sites [CGCG], got %v", result.PalindromicFusionSites)
+	}
+	if len(result.DuplicateFusionSites) != 0 || len(result.InternalSites) != 0 {
+		t.Errorf("Expected no duplicate fusion sites or internal sites, got %v and %v",
+			result.DuplicateFusionSites, result.InternalSites)
+	}
+}
+
+func TestGoldenGateReversedPart(t *testing.T) {
+	promoter := bsaIPart("promoter", "GGAG", "TTGACAATTAATCAT", "TACT")
+	cds := bsaIPart("cds", "TACT", "ATGAAACATCACCAT", "GGAG")
+	cds.Sequence = *NewFromWatsonStrand(transform.ReverseComplement(cds.Sequence.Watson), constants.Linear)
+
+	result, err := GoldenGate([]Part{promoter, cds}, db.Enzymes["BsaI"])
+	if err != nil {
+		t.Fatalf("Failed to simulate the assembly: %s", err)
+	}
+	if len(result.Products) != 1 {
+		t.Fatalf("Expected 1 product, got %d: %+v", len(result.Products), result.Products)
+	}
+
+	product := result.Products[0]
+	if !reflect.DeepEqual(product.Parts, []ProductPart{{"promoter", false}, {"cds", true}}) {
+		t.Errorf("Expected the cds to be reversed, got %v", product.Parts)
+	}
+	expectedSequence := "GGAGTTGACAATTAATCAT" + "TACTATGAAACATCACCAT"
+	if product.Sequence.Watson != expectedSequence {
+		t.Errorf("Expected product %s, got %s", expectedSequence, product.Sequence.Watson)
+	}
+}
+
+func TestGoldenGateDuplicateFusionSites(t *testing.T) {
+	promoter := bsaIPart("promoter", "GGAG", "TTGACAATTAATCAT", "TACT")
+	strongPromoter := bsaIPart("strongPromoter", "GGAG", "TTGACGGCTAGCTCA", "TACT")
+	cds := bsaIPart("cds", "TACT", "ATGAAACATCACCAT", "GGAG")
+
+	result, err := GoldenGate([]Part{promoter, strongPromoter, cds}, db.Enzymes["BsaI"])
+	if err != nil {
+		t.Fatalf("Failed to simulate the assembly: %s", err)
+	}
+
+	// Each promoter closes a circle with the cds.
+	if len(result.Products) != 2 {
+		t.Errorf("Expected 2 products, got %d: %+v", len(result.Products), result.Products)
+	}
+	if !reflect.DeepEqual(result.DuplicateFusionSites, []string{"AGTA", "CTCC"}) {
+		t.Errorf("Expected duplicate fusion sites [AGTA CTCC], got %v", result.DuplicateFusionSites)
+	}
+}
+
+func TestGoldenGateInternalSite(t *testing.T) {
+	promoter := bsaIPart("promoter", "GGAG", "TTGACAGGTCTCATTAATCAT", "TACT")
+	cds := bsaIPart("cds", "TACT", "ATGAAACATCACCAT", "GGAG")
+
+	result, err := GoldenGate([]Part{promoter, cds}, db.Enzymes["BsaI"])
+	if err != nil {
+		t.Fatalf("Failed to simulate the assembly: %s", err)
+	}
+
+	if len(result.InternalSites) != 1 || result.InternalSites[0].Part != "promoter" ||
+		result.InternalSites[0].Site.RecognitionSiteIndex != 17 {
+		t.Errorf("Expected an internal site in the promoter, got %+v", result.InternalSites)
+	}
+	if len(result.Products) != 0 || !reflect.DeepEqual(result.UnusedParts, []string{"promoter", "cds"}) {
+		t.Errorf("Expected the fragmented promoter to prevent assembly, got %+v", result)
+	}
+}
+
+func TestGoldenGateMultipleEnzymes(t *testing.T) {
+	promoter := bsaIPart("promoter", "GGAG", "TTGACAATTAATCAT", "TACT")
+	cds := Part{"cds", *NewFromWatsonStrand("CGTCTCA"+"TACT"+"ATGAAACATCACCAT"+"GGAG"+"AGAGACG", constants.Linear)}
+
+	result, err := GoldenGate([]Part{promoter, cds}, db.Enzymes["BsaI"])
+	if err != nil {
+		t.Fatalf("Failed to simulate the assembly: %s", err)
+	}
+	if len(result.Products) != 0 {
+		t.Errorf("Expected no products without BsmBI, got %+v", result.Products)
+	}
+
+	result, err = GoldenGate([]Part{promoter, cds}, db.Enzymes["BsaI"], db.Enzymes["BsmBI"])
+	if err != nil {
+		t.Fatalf("Failed to simulate the assembly: %s", err)
+	}
+	if len(result.Products) != 1 {
+		t.Errorf("Expected 1 product with BsaI and BsmBI, got %+v", result.Products)
+	}
+
+	if _, err := GoldenGate([]Part{promoter}, db.Enzymes["EcoRI"]); err == nil {
+		t.Errorf("Expected an error for an enzyme that is not Type IIS")
+	}
+}
+
+func TestGoldenGateTooManyOrderings(t *testing.T) {
+	// Every part can follow every other part, so the number of circles
+	// grows with the factorial of the number of parts.
+	parts := []Part{}
+	for i := 0; i < 12; i++ {
+		parts = append(parts, bsaIPart(fmt.Sprintf("part%d", i), "GGAG", "TTGACAATTAATCAT", "GGAG"))
+	}
+
+	if _, err := GoldenGate(parts, db.Enzymes["BsaI"]); err == nil {
+		t.Errorf("Expected an error for an assembly with too many orderings")
+	}
+}